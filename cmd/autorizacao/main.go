@@ -0,0 +1,103 @@
+// cmd\autorizacao\main.go
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+
+	"Gowpscanner/internal/utils"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v2"
+)
+
+// Ferramenta do arquivo de autorização e do log de auditoria:
+//
+//	go run ./cmd/autorizacao chaves <chave-privada>       -> gera um par Ed25519: grava a privada (0600) e
+//	                                                         imprime a linha da pública para chaves_aprovadores.txt
+//	go run ./cmd/autorizacao assinar <arquivo> <chave-privada> -> assina o arquivo de autorização
+//	go run ./cmd/autorizacao auditoria [arquivo]          -> confere a cadeia de hashes do log de auditoria
+//
+// A chave privada é do aprovador e fica num arquivo próprio, nunca no .env do scanner.
+func main() {
+	// O pacote utils descarta a saída do log; aqui os erros precisam aparecer
+	log.SetOutput(os.Stderr)
+	godotenv.Load()
+	if len(os.Args) < 2 {
+		log.Fatalf("Uso: %s chaves <chave-privada> | assinar <arquivo> <chave-privada> | auditoria [arquivo]", os.Args[0])
+	}
+
+	switch os.Args[1] {
+	case "chaves":
+		if len(os.Args) < 3 {
+			log.Fatal("Informe o arquivo onde gravar a chave privada")
+		}
+		publica, privada, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			log.Fatalf("Erro ao gerar as chaves: %v", err)
+		}
+		// O_EXCL: uma chave existente nunca é sobrescrita
+		f, err := os.OpenFile(os.Args[2], os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err != nil {
+			log.Fatalf("Erro ao criar %s: %v", os.Args[2], err)
+		}
+		if _, err := f.WriteString(hex.EncodeToString(privada) + "\n"); err != nil {
+			log.Fatalf("Erro ao gravar %s: %v", os.Args[2], err)
+		}
+		f.Close()
+		fmt.Printf("Chave privada gravada em %s (guarde-a fora do servidor de scan)\n", os.Args[2])
+		fmt.Println("Acrescente esta linha a internal/utils/chaves_aprovadores.txt e recompile o scanner:")
+		fmt.Printf("%s <nome do aprovador>\n", hex.EncodeToString(publica))
+
+	case "assinar":
+		if len(os.Args) < 4 {
+			log.Fatal("Informe o arquivo de autorização e o arquivo da chave privada")
+		}
+		conteudoChave, err := os.ReadFile(os.Args[3])
+		if err != nil {
+			log.Fatalf("Erro ao ler a chave privada %s: %v", os.Args[3], err)
+		}
+		chave, err := utils.DecodificarChave(string(conteudoChave))
+		if err != nil || len(chave) != ed25519.PrivateKeySize {
+			log.Fatalf("%s deve conter uma chave privada Ed25519 de 64 bytes (hex ou base64)", os.Args[3])
+		}
+		data, err := os.ReadFile(os.Args[2])
+		if err != nil {
+			log.Fatalf("Erro ao ler %s: %v", os.Args[2], err)
+		}
+		var a utils.Autorizacao
+		if err := yaml.Unmarshal(data, &a); err != nil {
+			log.Fatalf("Erro ao interpretar %s: %v", os.Args[2], err)
+		}
+		utils.AssinarAutorizacao(&a, ed25519.PrivateKey(chave))
+		saida, err := yaml.Marshal(&a)
+		if err != nil {
+			log.Fatalf("Erro ao gerar o YAML: %v", err)
+		}
+		if err := os.WriteFile(os.Args[2], saida, 0644); err != nil {
+			log.Fatalf("Erro ao gravar %s: %v", os.Args[2], err)
+		}
+		fmt.Printf("Autorização do engajamento %s assinada (%d domínios)\n", a.Engajamento, len(a.Dominios))
+
+	case "auditoria":
+		arquivo := "./retornos/auditoria.jsonl"
+		if val := os.Getenv("AUDITORIA_ARQUIVO"); val != "" {
+			arquivo = val
+		}
+		if len(os.Args) > 2 {
+			arquivo = os.Args[2]
+		}
+		n, ultimo, err := utils.VerificarAuditoria(arquivo)
+		if err != nil {
+			log.Fatalf("Log de auditoria inválido após %d registros: %v", n, err)
+		}
+		fmt.Printf("%d registros íntegros, último hash %s\n", n, ultimo)
+
+	default:
+		log.Fatalf("Comando desconhecido: %s", os.Args[1])
+	}
+}
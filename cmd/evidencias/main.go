@@ -0,0 +1,54 @@
+// cmd\evidencias\main.go
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+
+	"Gowpscanner/internal/utils"
+
+	"github.com/joho/godotenv"
+)
+
+// Decifra o arquivo de evidências gerado pelo scanner e exibe um registro JSON por linha.
+// Uso: go run ./cmd/evidencias [arquivo] (a chave é lida de EVIDENCIAS_CHAVE, no ambiente ou no .env)
+func main() {
+	// O pacote utils descarta a saída do log; aqui os erros precisam aparecer
+	log.SetOutput(os.Stderr)
+	godotenv.Load()
+
+	arquivo := "./retornos/evidencias.enc"
+	if val := os.Getenv("EVIDENCIAS_ARQUIVO"); val != "" {
+		arquivo = val
+	}
+	if len(os.Args) > 1 {
+		arquivo = os.Args[1]
+	}
+
+	aead, err := utils.NovaCifraEvidencias(os.Getenv("EVIDENCIAS_CHAVE"))
+	if err != nil {
+		log.Fatalf("Chave inválida: %v", err)
+	}
+
+	file, err := os.Open(arquivo)
+	if err != nil {
+		log.Fatalf("Erro ao abrir %s: %v", arquivo, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	linha := 0
+	for scanner.Scan() {
+		linha++
+		registro, err := utils.DecifrarEvidencia(aead, scanner.Text())
+		if err != nil {
+			log.Fatalf("Erro ao decifrar a linha %d (chave incorreta ou arquivo alterado): %v", linha, err)
+		}
+		fmt.Println(string(registro))
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatalf("Erro ao ler %s: %v", arquivo, err)
+	}
+}
@@ -3,6 +3,7 @@ module Gowpscanner
 go 1.23.5
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/EDDYCJY/fake-useragent v0.2.0
 	github.com/joho/godotenv v1.5.1
 	github.com/prometheus/client_golang v1.21.0
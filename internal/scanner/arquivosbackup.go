@@ -0,0 +1,131 @@
+// internal\scanner\arquivosbackup.go
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"Gowpscanner/internal/utils"
+)
+
+// backupList (carregada em init) contém os caminhos de paths/backups.txt.
+var backupList []string
+
+// extensoesBackup são as extensões consideradas arquivos de backup numa listagem de diretório.
+var extensoesBackup = []string{".zip", ".tar", ".tar.gz", ".tgz", ".gz", ".rar", ".7z", ".bz2", ".xz", ".sql", ".wpress", ".daf"}
+
+// CheckArquivosBackup procura arquivos de backup do site (zip, tar.gz, .wpress...) expostos.
+// Os achados são validados pelos magic bytes com uma leitura parcial (Range), sem baixar o arquivo inteiro.
+// Caminhos terminados em / são diretórios de plugins de backup: se a listagem estiver aberta,
+// os arquivos listados também são verificados.
+func CheckArquivosBackup(baseURL string) {
+	nomeBase, err := utils.ExtrairNomeBase(baseURL)
+	if err != nil {
+		return
+	}
+	host := hostSemWWW(baseURL)
+
+	var contador int = 0
+	for _, path := range backupList {
+		contador++
+		// Caso o contador seja múltiplo de 100, exibe mensagem
+		if contador%100 == 0 {
+			utils.Info("Verificando Arquivos de Backup %s - %d/%d", baseURL, contador, len(backupList))
+		}
+		path = expandirPlaceholders(path, nomeBase, host)
+		urlBackup := fmt.Sprintf("%s/%s", baseURL, strings.TrimPrefix(path, "/"))
+
+		if strings.HasSuffix(path, "/") {
+			verificarDiretorioBackup(urlBackup)
+			continue
+		}
+		verificarArquivoBackup(urlBackup)
+	}
+}
+
+// verificarDiretorioBackup lê a listagem do diretório (se aberta) e verifica os arquivos de backup listados.
+func verificarDiretorioBackup(urlDiretorio string) {
+	conteudo, err := utils.GetBody(urlDiretorio)
+	if err != nil || !ehListagemDiretorio(conteudo) {
+		return
+	}
+	for _, entrada := range extrairEntradasListagem(conteudo) {
+		if strings.HasSuffix(entrada, "/") || !temExtensaoBackup(entrada) {
+			continue
+		}
+		verificarArquivoBackup(urlDiretorio + entrada)
+	}
+}
+
+// verificarArquivoBackup baixa apenas o início do arquivo e registra o achado se o conteúdo for válido.
+func verificarArquivoBackup(urlArquivo string) {
+	resp, err := utils.GetPrefixo(urlArquivo, tamanhoPrefixoArquivo)
+	if err != nil {
+		return
+	}
+	descricao := identificarArquivo(resp, false)
+	if descricao == "" && strings.HasSuffix(strings.ToLower(urlArquivo), ".wpress") && pareceWpress(resp.Body) {
+		descricao = "backup All-in-One WP Migration (.wpress)"
+	}
+	if descricao == "" {
+		return
+	}
+
+	registro := fmt.Sprintf("%s - %s - tamanho: %s", urlArquivo, descricao, utils.FormatarTamanho(resp.Tamanho))
+	utils.LogSave(registro, "arquivos-backup.txt")
+	utils.Warning("Arquivo de backup exposto: %s", registro)
+	utils.BeepAlert()
+}
+
+// temExtensaoBackup indica se o nome do arquivo tem uma extensão de backup conhecida.
+func temExtensaoBackup(nome string) bool {
+	lower := strings.ToLower(nome)
+	for _, ext := range extensoesBackup {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// pareceWpress valida o cabeçalho do formato .wpress: nome do arquivo (255 bytes, completado com NUL)
+// seguido do tamanho em decimal (14 bytes).
+func pareceWpress(prefixo []byte) bool {
+	if len(prefixo) < 255+14 {
+		return false
+	}
+	nome := bytes.TrimRight(prefixo[:255], "\x00")
+	tamanho := bytes.TrimRight(prefixo[255:255+14], "\x00")
+	if len(nome) == 0 || len(tamanho) == 0 {
+		return false
+	}
+	for _, r := range string(nome) {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	for _, c := range tamanho {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// expandirPlaceholders troca {domain_name} pelo nome base do domínio e {host} pelo host sem www.
+func expandirPlaceholders(path, nomeBase, host string) string {
+	path = strings.ReplaceAll(path, "{domain_name}", nomeBase)
+	return strings.ReplaceAll(path, "{host}", host)
+}
+
+// hostSemWWW extrai o host (sem porta e sem www.) da URL base.
+func hostSemWWW(baseURL string) string {
+	partes := strings.Split(baseURL, "/")
+	if len(partes) < 3 {
+		return ""
+	}
+	host := strings.Split(partes[2], ":")[0]
+	return strings.TrimPrefix(host, "www.")
+}
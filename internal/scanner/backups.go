@@ -24,7 +24,8 @@ func CheckConfigBackups(baseURL string) {
 			utils.Info("Verificando Backups %s - %d/%d", baseURL, contador, len(configList))
 		}
 		urlConfig := fmt.Sprintf("%s/%s", baseURL, config)
-		conteudo, err := utils.GetBody(urlConfig)
+		// Backups compactados (ex.: wp-config.php.gz, wp-config.zip) são descompactados
+		conteudo, err := utils.GetBodyDescompactado(urlConfig, utils.LimiteBody("configs"))
 		if err != nil {
 			continue
 		}
@@ -39,6 +40,9 @@ func CheckConfigBackups(baseURL string) {
 		wpdetect.CheckFirebaseIO(conteudo)
 		wpdetect.CheckDigitalOceanToken(conteudo)
 		wpdetect.CheckAllTokens(conteudo, urlConfig)
+		if testarSegredosGenericos {
+			wpdetect.CheckSegredosGenericos(conteudo, urlConfig)
+		}
 
 		// Remove espaços em branco (se necessário para outras verificações, ex.: SMTP)
 		conteudo = strings.ReplaceAll(conteudo, " ", "")
@@ -57,6 +61,10 @@ func CheckConfigBackups(baseURL string) {
 			//troca 127.0.0.1 pelo tmphost
 			dbhost = strings.Replace(dbhost, "127.0.0.1", tmphost, -1)
 			configValues["DB_HOST"] = dbhost
+			// A senha é gravada mascarada; o valor completo fica só no arquivo de evidências
+			if senha, ok := configValues["DB_PASSWORD"]; ok {
+				configValues["DB_PASSWORD"] = utils.RegistrarSegredo(senha, "DB_PASSWORD - "+urlConfig)
+			}
 			// Monta a string com os valores extraídos
 			var configOutput string
 			configOutput += fmt.Sprintf("URL: %s", urlConfig)
@@ -27,7 +27,7 @@ func CheckShell(baseURL string) {
 			buscatmp = parts[1]
 		}
 		urlConfig := fmt.Sprintf("%s/%s", baseURL, shellpath)
-		conteudo, err := utils.GetBody(urlConfig)
+		conteudo, err := utils.GetBodyLimitado(urlConfig, utils.LimiteBody("shells"))
 		if err != nil {
 			continue
 		}
@@ -0,0 +1,127 @@
+// internal\scanner\dbexports.go
+package scanner
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"Gowpscanner/internal/utils"
+)
+
+// tamanhoPrefixoArquivo é quanto de cada arquivo suspeito é baixado para validar o conteúdo.
+const tamanhoPrefixoArquivo = 64 * 1024
+
+// CheckDBExports procura dumps de banco de dados expostos (lista database/db_exports.txt),
+// validando o conteúdo pelos primeiros bytes, sem baixar o arquivo inteiro.
+func CheckDBExports(baseURL string) {
+	nomeBase, err := utils.ExtrairNomeBase(baseURL)
+	if err != nil {
+		return
+	}
+
+	var contador int = 0
+	for _, path := range dbExportsList {
+		contador++
+		// Caso o contador seja múltiplo de 100, exibe mensagem
+		if contador%100 == 0 {
+			utils.Info("Verificando DB Exports %s - %d/%d", baseURL, contador, len(dbExportsList))
+		}
+		// Expande os placeholders no estilo {domain_name}
+		path = expandirPlaceholders(path, nomeBase, hostSemWWW(baseURL))
+		urlExport := fmt.Sprintf("%s/%s", baseURL, strings.TrimPrefix(path, "/"))
+
+		resp, err := utils.GetPrefixo(urlExport, tamanhoPrefixoArquivo)
+		if err != nil {
+			continue
+		}
+		descricao := identificarArquivo(resp, true)
+		if descricao == "" {
+			continue
+		}
+
+		registro := fmt.Sprintf("%s - %s - tamanho: %s", urlExport, descricao, utils.FormatarTamanho(resp.Tamanho))
+		utils.LogSave(registro, "dbexports.txt")
+		utils.Warning("Dump de banco de dados exposto: %s", registro)
+		utils.BeepAlert()
+	}
+}
+
+// identificarArquivo valida, pelos primeiros bytes da resposta, se ela é um dump SQL ou um arquivo
+// compactado. Com somenteSQL, arquivos compactados só são aceitos se contiverem SQL (ou um .sql
+// no caso do zip). Retorna a descrição do conteúdo, ou "" se não for um arquivo válido.
+func identificarArquivo(resp *utils.Resposta, somenteSQL bool) string {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return ""
+	}
+	inicio := resp.Body
+	if len(inicio) > 1024 {
+		inicio = inicio[:1024]
+	}
+	lower := strings.ToLower(string(inicio))
+	// Páginas de erro "soft 404" costumam ser HTML
+	if strings.Contains(lower, "<html") || strings.Contains(lower, "<!doctype") {
+		return ""
+	}
+
+	switch resp.Tipo {
+	case "sql":
+		return "dump SQL"
+	case "gzip":
+		if trecho := descompactarPrefixoGzip(resp.Body); utils.ContemAssinaturaSQL(trecho) {
+			return "dump SQL (gzip)"
+		}
+		if !somenteSQL {
+			return "arquivo gzip"
+		}
+	case "zip":
+		nome := nomePrimeiroArquivoZip(resp.Body)
+		if strings.HasSuffix(strings.ToLower(nome), ".sql") {
+			return fmt.Sprintf("dump SQL (zip: %s)", nome)
+		}
+		if !somenteSQL {
+			if nome != "" {
+				return fmt.Sprintf("arquivo zip (%s)", nome)
+			}
+			return "arquivo zip"
+		}
+	case "texto":
+		// O cabeçalho do dump pode vir depois de comentários longos
+		if utils.ContemAssinaturaSQL(resp.Body) {
+			return "dump SQL"
+		}
+	case "tar", "rar", "7z", "bzip2", "xz", "zstd":
+		if !somenteSQL {
+			return "arquivo " + resp.Tipo
+		}
+	}
+	return ""
+}
+
+// descompactarPrefixoGzip descompacta o que for possível de um gzip parcial (apenas o início).
+func descompactarPrefixoGzip(prefixo []byte) []byte {
+	gz, err := gzip.NewReader(bytes.NewReader(prefixo))
+	if err != nil {
+		return nil
+	}
+	defer gz.Close()
+	trecho, _ := io.ReadAll(io.LimitReader(gz, 8*1024))
+	return trecho
+}
+
+// nomePrimeiroArquivoZip lê o nome do primeiro arquivo no cabeçalho local do zip.
+func nomePrimeiroArquivoZip(prefixo []byte) string {
+	// Cabeçalho local: assinatura (4) ... tamanho do nome nos bytes 26-27, nome a partir do byte 30
+	if len(prefixo) < 30 || !bytes.HasPrefix(prefixo, []byte("PK\x03\x04")) {
+		return ""
+	}
+	tamanhoNome := int(binary.LittleEndian.Uint16(prefixo[26:28]))
+	if len(prefixo) < 30+tamanhoNome {
+		return ""
+	}
+	return string(prefixo[30 : 30+tamanhoNome])
+}
@@ -4,6 +4,7 @@ package scanner
 import (
 	"Gowpscanner/internal/utils"
 	"Gowpscanner/internal/wpdetect"
+	"net"
 	"strings"
 )
 
@@ -12,6 +13,23 @@ func processDomain(dominio string) {
 	//retira o http:// e https:// do dominio
 	dominio = strings.Replace(dominio, "http://", "", -1)
 	dominio = strings.Replace(dominio, "https://", "", -1)
+	// Alvos fora do escopo (nem com www.) não são escaneados; as requisições seriam bloqueadas de qualquer forma
+	host := hostDoAlvo(dominio)
+	// Descarta as respostas em cache de todos os hosts acessados por este alvo ao final do scan: o próprio
+	// host, as variações testadas por IsWordPress (www., blog.), a URL final e os sites relacionados
+	semWWW := strings.TrimPrefix(host, "www.")
+	hostsAcessados := []string{host, "www." + host, "www." + semWWW, "blog." + semWWW}
+	defer func() { utils.LimparCache(hostsAcessados...) }()
+
+	if err := utils.VerificarEscopo(host); err != nil && utils.VerificarEscopo("www."+host) != nil {
+		utils.Error("%s ignorado: %v", dominio, err)
+		return
+	}
+	// Pré-resolução DNS: alvos sem resposta (nem com www.) são descartados antes das tentativas HTTP
+	if utils.PreResolverDNS && !resolverAlvo(dominio) {
+		utils.Error("%s não possui resposta DNS (A/AAAA), ignorando", dominio)
+		return
+	}
 	// Testa HTTPS
 	urlHTTPS := "https://" + dominio
 	okHTTPS := utils.TestURL(urlHTTPS)
@@ -19,14 +37,14 @@ func processDomain(dominio string) {
 	if okHTTPS {
 		valido, novaURL := wpdetect.IsWordPress(urlHTTPS)
 		if valido {
-			utils.LogSave(novaURL, "wordpress.txt")
-			CheckConfigBackups(novaURL)
-			CheckPlugins(novaURL, dominio)
-			CheckThemes(novaURL, dominio)
-			CheckShell(novaURL)
+			escanearWordPress(novaURL, dominio)
+			hostsAcessados = append(hostsAcessados, hostDoAlvo(novaURL))
+			hostsAcessados = append(hostsAcessados, CheckSitesRelacionados(novaURL, dominio)...)
 		} else {
 			utils.Info("%s não parece ser WordPress", dominio)
 			CheckShell(urlHTTPS)
+			CheckVCS(urlHTTPS)
+			CheckDebugLog(urlHTTPS)
 			CheckEnv(urlHTTPS)
 		}
 	} else {
@@ -36,15 +54,15 @@ func processDomain(dominio string) {
 		if okHTTP {
 			valido, novaURL := wpdetect.IsWordPress(urlHTTP)
 			if valido {
-				utils.LogSave(novaURL, "wordpress.txt")
-				CheckConfigBackups(novaURL)
-				CheckPlugins(novaURL, dominio)
-				CheckThemes(novaURL, dominio)
-				CheckShell(novaURL)
+				escanearWordPress(novaURL, dominio)
+				hostsAcessados = append(hostsAcessados, hostDoAlvo(novaURL))
+				hostsAcessados = append(hostsAcessados, CheckSitesRelacionados(novaURL, dominio)...)
 				CheckYaml(novaURL)
 			} else {
 				utils.Info("%s não parece ser WordPress", dominio)
 				CheckShell(urlHTTP)
+				CheckVCS(urlHTTP)
+				CheckDebugLog(urlHTTP)
 				CheckEnv(urlHTTP)
 				CheckYaml(urlHTTP)
 			}
@@ -55,6 +73,8 @@ func processDomain(dominio string) {
 			if okHTTPS2 {
 				utils.Info("%s não parece ser WordPress", dominio)
 				CheckShell(urlHTTPS2)
+				CheckVCS(urlHTTPS2)
+				CheckDebugLog(urlHTTPS2)
 				CheckEnv(urlHTTPS2)
 				CheckYaml(urlHTTPS2)
 			} else {
@@ -64,6 +84,8 @@ func processDomain(dominio string) {
 				if okHTTP2 {
 					utils.Info("%s não parece ser WordPress", dominio)
 					CheckShell(urlHTTP2)
+					CheckVCS(urlHTTP2)
+					CheckDebugLog(urlHTTP2)
 					CheckEnv(urlHTTP2)
 					CheckYaml(urlHTTP2)
 				} else {
@@ -73,3 +95,46 @@ func processDomain(dominio string) {
 		}
 	}
 }
+
+// escanearWordPress executa todas as checagens de WordPress numa instalação.
+func escanearWordPress(novaURL, dominio string) {
+	utils.LogSave(novaURL, "wordpress.txt")
+	CheckConfigBackups(novaURL)
+	CheckHardening(novaURL)
+	CheckRestAPI(novaURL)
+	CheckUsuarios(novaURL)
+	CheckDBExports(novaURL)
+	CheckArquivosBackup(novaURL)
+	inventario := CheckListagemDiretorios(novaURL)
+	CheckPlugins(novaURL, dominio, inventario.Plugins)
+	CheckThemes(novaURL, dominio, inventario.Temas)
+	CheckShell(novaURL)
+	CheckVCS(novaURL)
+	CheckDebugLog(novaURL)
+}
+
+// resolverAlvo resolve o domínio e a variação www., registrando IPs e CNAMEs em dns.txt.
+// Retorna false se nenhuma das duas variações tiver resposta DNS.
+func resolverAlvo(dominio string) bool {
+	host := hostDoAlvo(dominio)
+	resolvido := false
+	for _, h := range []string{host, "www." + host} {
+		r, err := utils.ResolverHost(h)
+		if err != nil {
+			continue
+		}
+		utils.RegistrarDNS(r)
+		resolvido = true
+	}
+	return resolvido
+}
+
+// hostDoAlvo extrai o host de uma linha da lista de alvos, sem esquema, caminho e porta
+// (ex.: https://example.com:8443/blog -> example.com).
+func hostDoAlvo(dominio string) string {
+	host := strings.Split(strings.TrimPrefix(strings.TrimPrefix(dominio, "https://"), "http://"), "/")[0]
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return host
+}
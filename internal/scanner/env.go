@@ -27,8 +27,9 @@ func CheckEnv(baseURL string) {
 
 		envURL := fmt.Sprintf("%s%s", baseURL, p)
 
-		// Tenta obter o conteúdo usando GetBody (que já retorna erro se o status não for 200)
-		content, err := utils.GetBody(envURL)
+		// Tenta obter o conteúdo usando GetBodyDescompactado (que já retorna erro se o status não for 200,
+		// se o corpo passar do limite ou se for um arquivo binário que não seja gzip/zip)
+		content, err := utils.GetBodyDescompactado(envURL, utils.LimiteBody("env"))
 		if err != nil {
 			// Se ocorrer algum erro, não há .env acessível nesse caminho
 			continue
@@ -46,6 +47,9 @@ func CheckEnv(baseURL string) {
 		wpdetect.CheckFirebaseIO(content)
 		wpdetect.CheckDigitalOceanToken(content)
 		wpdetect.CheckAllTokens(content, envURL)
+		if testarSegredosGenericos {
+			wpdetect.CheckSegredosGenericos(content, envURL)
+		}
 
 		// Verifica se o conteúdo contém algumas chaves típicas de um arquivo .env
 		if strings.Contains(lowerContent, "app_name=") ||
@@ -97,6 +101,8 @@ func CheckEnv(baseURL string) {
 							envValues["DB_PASS"] == "null" {
 							utils.Info("DB_USERNAME, DB_PASSWORD, DB_DATABASE, DB_USER ou DB_PASS é nulo ou vazio, dados não serão salvos em mysqlconfigs.txt")
 						} else {
+							// As senhas são gravadas mascaradas; o valor completo fica só no arquivo de evidências
+							registrarSenhasEnv(envValues, envURL, "DB_PASSWORD", "DB_PASS")
 							var envOutput string = fmt.Sprintf("URL: %s HOST:%s USERNAME:%s%s PASSWORD:%s%s DATABASE:%s", envURL, envValues["DB_HOST"], envValues["DB_USERNAME"], envValues["DB_USER"], envValues["DB_PASSWORD"], envValues["DB_PASS"], envValues["DB_DATABASE"])
 
 							// Salva a saída no arquivo mysqlconfigs.txt
@@ -124,6 +130,7 @@ func CheckEnv(baseURL string) {
 						if envValues["MAIL_USERNAME"] == "null" || envValues["MAIL_PASSWORD"] == "null" || envValues["MAIL_USER"] == "null" {
 							utils.Info("MAIL_USERNAME, MAIL_PASSWORD ou MAIL_USER é nulo ou vazio, dados não serão salvos em smtpconfigs.txt")
 						} else {
+							registrarSenhasEnv(envValues, envURL, "MAIL_PASSWORD", "MAIL_PASS")
 							var envOutput string = fmt.Sprintf("URL: %s MAIL_HOST:%s MAIL_USERNAME:%s%s MAIL_PASSWORD:%s%s", envURL, envValues["MAIL_HOST"], envValues["MAIL_USERNAME"], envValues["MAIL_USER"], envValues["MAIL_PASS"], envValues["MAIL_PASSWORD"])
 							// Salva a saída no arquivo smtpconfigs.txt
 							utils.LogSave(envOutput, "smtpconfigs.txt")
@@ -167,3 +174,12 @@ func extractENVValues(content string) map[string]string {
 
 	return envMap
 }
+
+// registrarSenhasEnv troca as senhas extraídas do .env pela forma de exibição (mascarada por padrão).
+func registrarSenhasEnv(envValues map[string]string, envURL string, campos ...string) {
+	for _, campo := range campos {
+		if senha, ok := envValues[campo]; ok && senha != "" {
+			envValues[campo] = utils.RegistrarSegredo(senha, campo+" - "+envURL)
+		}
+	}
+}
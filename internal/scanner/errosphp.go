@@ -0,0 +1,177 @@
+// internal\scanner\errosphp.go
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"Gowpscanner/internal/utils"
+)
+
+// logsList e fpdList (carregadas em init) contêm os caminhos de paths/logs.txt e paths/fpd.txt.
+var logsList []string
+var fpdList []string
+
+var (
+	// reErroPHP reconhece mensagens de erro do PHP, no formato HTML (display_errors) ou de log.
+	reErroPHP = regexp.MustCompile(`(?i)(?:<b>|PHP\s+)(Fatal error|Parse error|Warning|Notice|Deprecated|Catchable fatal error)(?:</b>)?:`)
+	// reCaminhoPHP captura caminhos absolutos de arquivos PHP (Unix ou Windows).
+	reCaminhoPHP  = regexp.MustCompile(`(?:/[\w.\-@~+]+)+/[\w.\-@~+]+\.php|[A-Za-z]:\\(?:[\w.\-@~+ ]+\\)+[\w.\-@~+]+\.php`)
+	rePluginLog   = regexp.MustCompile(`wp-content[/\\]plugins[/\\]([\w.\-]+)[/\\]`)
+	reTemaLog     = regexp.MustCompile(`wp-content[/\\]themes[/\\]([\w.\-]+)[/\\]`)
+	reVersaoPHP   = regexp.MustCompile(`(?i)\bPHP(?:\s+version)?[\s/:]+v?(\d+\.\d+\.\d+)`)
+	reLinhaLogPHP = regexp.MustCompile(`(?m)^\[\d{2}-[A-Za-z]{3}-\d{4} \d{2}:\d{2}:\d{2}[^\]]*\]\s+PHP `)
+
+	// caminhosRegistrados evita registrar o mesmo caminho vazado várias vezes.
+	caminhosRegistrados sync.Map
+)
+
+// evidenciasPHP reúne o que um log ou página de erro revela sobre o servidor.
+type evidenciasPHP struct {
+	Caminhos   []string
+	Plugins    []string
+	Temas      []string
+	VersoesPHP []string
+}
+
+// vazio indica se nenhuma evidência foi encontrada.
+func (e evidenciasPHP) vazio() bool {
+	return len(e.Caminhos) == 0 && len(e.Plugins) == 0 && len(e.Temas) == 0 && len(e.VersoesPHP) == 0
+}
+
+// extrairEvidenciasPHP extrai caminhos absolutos, plugins, temas e versões do PHP de um log ou página de erro.
+func extrairEvidenciasPHP(conteudo string) evidenciasPHP {
+	var e evidenciasPHP
+	e.Caminhos = unicos(reCaminhoPHP.FindAllString(conteudo, -1))
+	for _, m := range rePluginLog.FindAllStringSubmatch(conteudo, -1) {
+		e.Plugins = append(e.Plugins, m[1])
+	}
+	for _, m := range reTemaLog.FindAllStringSubmatch(conteudo, -1) {
+		e.Temas = append(e.Temas, m[1])
+	}
+	for _, m := range reVersaoPHP.FindAllStringSubmatch(conteudo, -1) {
+		e.VersoesPHP = append(e.VersoesPHP, m[1])
+	}
+	e.Plugins = unicos(e.Plugins)
+	e.Temas = unicos(e.Temas)
+	e.VersoesPHP = unicos(e.VersoesPHP)
+	return e
+}
+
+// CheckDebugLog procura o wp-content/debug.log e outros logs de erro do PHP expostos,
+// e páginas de erro fatal que vazam caminhos absolutos ao acessar arquivos diretamente.
+func CheckDebugLog(baseURL string) {
+	for _, path := range logsList {
+		urlLog := fmt.Sprintf("%s/%s", baseURL, path)
+		// Logs grandes são os mais comuns: só o início (até o limite) é baixado e analisado
+		resp, err := utils.GetPrefixo(urlLog, utils.LimiteBody("logs"))
+		if err != nil || (resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent) ||
+			utils.TipoBinario(resp.Tipo) {
+			continue
+		}
+		conteudo := string(resp.Body)
+		truncado := resp.Truncado || (resp.Tamanho > 0 && int64(len(resp.Body)) < resp.Tamanho)
+		if truncado {
+			// Descarta a última linha, que pode ter sido cortada no meio
+			if idx := strings.LastIndex(conteudo, "\n"); idx != -1 {
+				conteudo = conteudo[:idx]
+			}
+		}
+		// Um log de erros do PHP tem linhas "[dd-Mon-aaaa hh:mm:ss UTC] PHP ..." ou mensagens de erro com arquivo e linha
+		if !reLinhaLogPHP.MatchString(conteudo) && !reErroPHP.MatchString(conteudo) {
+			continue
+		}
+		e := extrairEvidenciasPHP(conteudo)
+		registro := fmt.Sprintf("%s - log de erros exposto%s", urlLog, formatarEvidencias(e))
+		if truncado {
+			tamanho := "desconhecido"
+			if resp.Tamanho > 0 {
+				tamanho = fmt.Sprintf("%d bytes", resp.Tamanho)
+			}
+			registro += fmt.Sprintf(" - log truncado: analisados os primeiros %d bytes (tamanho total: %s)", len(resp.Body), tamanho)
+		}
+		utils.LogSave(registro, "debuglog.txt")
+		utils.Warning("Log de erros do PHP exposto: %s", registro)
+		utils.BeepAlert()
+		for _, caminho := range e.Caminhos {
+			registrarCaminhoVazado(urlLog, caminho)
+		}
+	}
+
+	for _, path := range fpdList {
+		urlFPD := fmt.Sprintf("%s/%s", baseURL, path)
+		// Páginas de erro fatal costumam vir com status 200 ou 500
+		resp, err := utils.Buscar(urlFPD)
+		if err != nil || resp.Truncado {
+			continue
+		}
+		registrarErroPHP(urlFPD, string(resp.Body))
+	}
+}
+
+// registrarErroPHP registra os caminhos absolutos revelados por uma página de erro do PHP.
+// Retorna true se a página continha um erro com caminho.
+func registrarErroPHP(urlRef, conteudo string) bool {
+	if !reErroPHP.MatchString(conteudo) {
+		return false
+	}
+	caminhos := unicos(reCaminhoPHP.FindAllString(conteudo, -1))
+	for _, caminho := range caminhos {
+		registrarCaminhoVazado(urlRef, caminho)
+	}
+	return len(caminhos) > 0
+}
+
+// registrarCaminhoVazado salva (uma única vez) um caminho absoluto do servidor vazado numa URL.
+func registrarCaminhoVazado(urlRef, caminho string) {
+	host := hostSemWWW(urlRef)
+	if _, existe := caminhosRegistrados.LoadOrStore(host+"|"+caminho, true); existe {
+		return
+	}
+	registro := fmt.Sprintf("%s - caminho do servidor: %s", urlRef, caminho)
+	utils.LogSave(registro, "caminhos-vazados.txt")
+	utils.Warning("Caminho absoluto vazado: %s", registro)
+}
+
+// formatarEvidencias monta o trecho " - caminhos: ... - plugins: ..." de um registro.
+func formatarEvidencias(e evidenciasPHP) string {
+	if e.vazio() {
+		return ""
+	}
+	var partes []string
+	if len(e.Caminhos) > 0 {
+		caminhos := e.Caminhos
+		if len(caminhos) > 5 {
+			caminhos = append(caminhos[:5:5], fmt.Sprintf("(+%d)", len(e.Caminhos)-5))
+		}
+		partes = append(partes, "caminhos: "+strings.Join(caminhos, ", "))
+	}
+	if len(e.Plugins) > 0 {
+		partes = append(partes, "plugins: "+strings.Join(e.Plugins, ", "))
+	}
+	if len(e.Temas) > 0 {
+		partes = append(partes, "temas: "+strings.Join(e.Temas, ", "))
+	}
+	if len(e.VersoesPHP) > 0 {
+		partes = append(partes, "PHP: "+strings.Join(e.VersoesPHP, ", "))
+	}
+	return " - " + strings.Join(partes, " - ")
+}
+
+// unicos remove duplicatas e ordena a lista.
+func unicos(lista []string) []string {
+	vistos := make(map[string]bool)
+	var resultado []string
+	for _, item := range lista {
+		if !vistos[item] {
+			vistos[item] = true
+			resultado = append(resultado, item)
+		}
+	}
+	sort.Strings(resultado)
+	return resultado
+}
@@ -0,0 +1,305 @@
+// internal\scanner\hardening.go
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"Gowpscanner/internal/utils"
+)
+
+// Resultados possíveis de um controle de hardening.
+const (
+	hardeningPassou = "PASS"
+	hardeningFalhou = "FAIL"
+	hardeningNA     = "N/A"
+)
+
+// maxRedirecionamentosHardening limita os redirecionamentos seguidos no wp-login.php e no wp-admin.
+const maxRedirecionamentosHardening = 5
+
+// marcadoresProtecaoLogin são trechos (em minúsculas) de páginas de WAF, desafio ou captcha que
+// protegem o login mesmo com status 200.
+var marcadoresProtecaoLogin = []string{"captcha", "cf-chl", "challenge-platform", "sucuri", "wordfence", "incapsula", "mod_security", "request blocked"}
+
+// cookiesIgnoradosHardening são cookies do core sem valor de sessão: o wordpress_test_cookie só indica
+// que o navegador aceita cookies e nunca tem HttpOnly/SameSite.
+var cookiesIgnoradosHardening = map[string]bool{"wordpress_test_cookie": true}
+
+var (
+	reMaxAge       = regexp.MustCompile(`(?i)max-age\s*=\s*"?(\d+)`)
+	reVersaoHeader = regexp.MustCompile(`/\d+(\.\d+)*`)
+	reVersaoPHPHdr = regexp.MustCompile(`(?i)PHP/(\d+\.\d+(?:\.\d+)?)`)
+)
+
+// controleHardening é o resultado de um controle do relatório de hardening.
+type controleHardening struct {
+	nome      string
+	resultado string
+	detalhe   string
+}
+
+// CheckHardening monta o relatório de hardening do alvo: cabeçalhos de segurança (HSTS, CSP,
+// X-Frame-Options), flags dos cookies do wp-login.php, vazamento de versões (Server, X-Powered-By, PHP)
+// e exposição do wp-login.php/wp-admin. Cada controle é salvo em hardening.txt como PASS, FAIL ou N/A.
+func CheckHardening(baseURL string) {
+	if !testarHardening {
+		return
+	}
+	home, err := utils.Buscar(baseURL + "/")
+	if err != nil {
+		return
+	}
+	// login fica nil se o wp-login.php não responder; redirecionamentos (http -> https, www.) são seguidos
+	login, _, _ := seguirRedirecionamentos(baseURL + "/wp-login.php")
+	https := strings.HasPrefix(baseURL, "https://")
+
+	controles := []controleHardening{
+		controleHSTS(home, https),
+		controleCSP(home),
+		controleXFO(home),
+		controleCookies(login, https),
+		controleServer(home),
+		controleXPoweredBy(home),
+		controleVersaoPHP(home, login),
+		controleWPLogin(login),
+		controleWPAdmin(baseURL),
+	}
+
+	aprovados, reprovados := 0, 0
+	for _, c := range controles {
+		utils.LogSave(fmt.Sprintf("%s - %s: %s - %s", baseURL, c.nome, c.resultado, c.detalhe), "hardening.txt")
+		switch c.resultado {
+		case hardeningPassou:
+			aprovados++
+		case hardeningFalhou:
+			reprovados++
+		}
+	}
+	utils.Info("Hardening %s: %d controles aprovados, %d reprovados (detalhes em retornos/hardening.txt)", baseURL, aprovados, reprovados)
+}
+
+// controleHSTS exige Strict-Transport-Security com max-age de pelo menos 6 meses.
+func controleHSTS(home *utils.Resposta, https bool) controleHardening {
+	c := controleHardening{nome: "HSTS"}
+	if !https {
+		c.resultado, c.detalhe = hardeningFalhou, "site servido sem HTTPS"
+		return c
+	}
+	hsts := home.Header.Get("Strict-Transport-Security")
+	m := reMaxAge.FindStringSubmatch(hsts)
+	if m == nil {
+		c.resultado, c.detalhe = hardeningFalhou, "cabeçalho Strict-Transport-Security ausente"
+		return c
+	}
+	if maxAge, _ := strconv.Atoi(m[1]); maxAge < 15552000 {
+		c.resultado, c.detalhe = hardeningFalhou, "max-age menor que 6 meses: "+hsts
+		return c
+	}
+	c.resultado, c.detalhe = hardeningPassou, hsts
+	return c
+}
+
+// controleCSP exige um cabeçalho Content-Security-Policy (o modo Report-Only não protege).
+func controleCSP(home *utils.Resposta) controleHardening {
+	c := controleHardening{nome: "CSP"}
+	if csp := home.Header.Get("Content-Security-Policy"); csp != "" {
+		c.resultado, c.detalhe = hardeningPassou, csp
+	} else if home.Header.Get("Content-Security-Policy-Report-Only") != "" {
+		c.resultado, c.detalhe = hardeningFalhou, "apenas Content-Security-Policy-Report-Only"
+	} else {
+		c.resultado, c.detalhe = hardeningFalhou, "cabeçalho Content-Security-Policy ausente"
+	}
+	return c
+}
+
+// controleXFO exige X-Frame-Options (DENY/SAMEORIGIN) ou frame-ancestors na CSP.
+func controleXFO(home *utils.Resposta) controleHardening {
+	c := controleHardening{nome: "X-Frame-Options"}
+	xfo := strings.ToUpper(strings.TrimSpace(home.Header.Get("X-Frame-Options")))
+	switch {
+	case xfo == "DENY" || xfo == "SAMEORIGIN":
+		c.resultado, c.detalhe = hardeningPassou, xfo
+	case strings.Contains(home.Header.Get("Content-Security-Policy"), "frame-ancestors"):
+		c.resultado, c.detalhe = hardeningPassou, "frame-ancestors na CSP"
+	case xfo != "":
+		c.resultado, c.detalhe = hardeningFalhou, "valor inválido: "+xfo
+	default:
+		c.resultado, c.detalhe = hardeningFalhou, "cabeçalho X-Frame-Options ausente"
+	}
+	return c
+}
+
+// seguirRedirecionamentos busca a URL seguindo até maxRedirecionamentosHardening redirecionamentos
+// (o cliente compartilhado não os segue). Retorna a última resposta e as URLs visitadas, em ordem.
+func seguirRedirecionamentos(alvo string) (*utils.Resposta, []string, error) {
+	visitadas := []string{alvo}
+	for i := 0; ; i++ {
+		resp, err := utils.Buscar(alvo)
+		if err != nil {
+			return nil, visitadas, err
+		}
+		local := resp.Header.Get("Location")
+		if resp.StatusCode < 300 || resp.StatusCode >= 400 || local == "" || i == maxRedirecionamentosHardening {
+			return resp, visitadas, nil
+		}
+		atual, err := url.Parse(alvo)
+		if err != nil {
+			return resp, visitadas, nil
+		}
+		proxima, err := atual.Parse(local)
+		if err != nil {
+			return resp, visitadas, nil
+		}
+		alvo = proxima.String()
+		visitadas = append(visitadas, alvo)
+	}
+}
+
+// marcadorProtecao retorna o marcador de WAF/captcha encontrado no corpo, ou "".
+func marcadorProtecao(corpo []byte) string {
+	lower := strings.ToLower(string(corpo))
+	for _, m := range marcadoresProtecaoLogin {
+		if strings.Contains(lower, m) {
+			return m
+		}
+	}
+	return ""
+}
+
+// controleCookies exige HttpOnly, SameSite e (em HTTPS) Secure nos cookies definidos pelo wp-login.php,
+// exceto os cookies do core sem valor de sessão (cookiesIgnoradosHardening).
+func controleCookies(login *utils.Resposta, https bool) controleHardening {
+	c := controleHardening{nome: "Cookies do wp-login.php"}
+	if login == nil {
+		c.resultado, c.detalhe = hardeningNA, "wp-login.php inacessível"
+		return c
+	}
+	var cookies []*http.Cookie
+	for _, ck := range (&http.Response{Header: login.Header}).Cookies() {
+		if !cookiesIgnoradosHardening[ck.Name] {
+			cookies = append(cookies, ck)
+		}
+	}
+	if len(cookies) == 0 {
+		c.resultado, c.detalhe = hardeningNA, "nenhum cookie de sessão definido"
+		return c
+	}
+	var problemas []string
+	for _, ck := range cookies {
+		var faltando []string
+		if https && !ck.Secure {
+			faltando = append(faltando, "Secure")
+		}
+		if !ck.HttpOnly {
+			faltando = append(faltando, "HttpOnly")
+		}
+		if ck.SameSite == http.SameSiteDefaultMode {
+			faltando = append(faltando, "SameSite")
+		}
+		if len(faltando) > 0 {
+			problemas = append(problemas, fmt.Sprintf("%s sem %s", ck.Name, strings.Join(faltando, "/")))
+		}
+	}
+	if len(problemas) > 0 {
+		c.resultado, c.detalhe = hardeningFalhou, strings.Join(problemas, ", ")
+		return c
+	}
+	c.resultado, c.detalhe = hardeningPassou, fmt.Sprintf("%d cookies com as flags exigidas", len(cookies))
+	return c
+}
+
+// controleServer reprova o cabeçalho Server com número de versão.
+func controleServer(home *utils.Resposta) controleHardening {
+	c := controleHardening{nome: "Versão no cabeçalho Server"}
+	server := home.Header.Get("Server")
+	if reVersaoHeader.MatchString(server) {
+		c.resultado, c.detalhe = hardeningFalhou, "Server: "+server
+		return c
+	}
+	c.resultado, c.detalhe = hardeningPassou, "Server: "+server
+	return c
+}
+
+// controleXPoweredBy reprova a presença do cabeçalho X-Powered-By.
+func controleXPoweredBy(home *utils.Resposta) controleHardening {
+	c := controleHardening{nome: "X-Powered-By"}
+	if xpb := home.Header.Values("X-Powered-By"); len(xpb) > 0 {
+		c.resultado, c.detalhe = hardeningFalhou, "X-Powered-By: "+strings.Join(xpb, ", ")
+		return c
+	}
+	c.resultado, c.detalhe = hardeningPassou, "cabeçalho ausente"
+	return c
+}
+
+// controleVersaoPHP reprova a divulgação da versão do PHP em qualquer cabeçalho da página inicial ou do login.
+func controleVersaoPHP(respostas ...*utils.Resposta) controleHardening {
+	c := controleHardening{nome: "Versão do PHP"}
+	for _, resp := range respostas {
+		if resp == nil {
+			continue
+		}
+		for nome, valores := range resp.Header {
+			for _, valor := range valores {
+				if m := reVersaoPHPHdr.FindStringSubmatch(valor); m != nil {
+					c.resultado, c.detalhe = hardeningFalhou, fmt.Sprintf("PHP %s em %s", m[1], nome)
+					return c
+				}
+			}
+		}
+	}
+	c.resultado, c.detalhe = hardeningPassou, "versão não divulgada nos cabeçalhos"
+	return c
+}
+
+// controleWPLogin reprova o formulário de login acessível publicamente (resposta já com os redirecionamentos
+// seguidos). Só conta como protegido o 401/403 (autenticação, restrição por IP) ou uma página de WAF/captcha;
+// redirecionamentos que não terminam, 404 (caminho base errado) e outros status são inconclusivos.
+func controleWPLogin(login *utils.Resposta) controleHardening {
+	c := controleHardening{nome: "wp-login.php"}
+	if login == nil {
+		c.resultado, c.detalhe = hardeningNA, "wp-login.php inacessível"
+		return c
+	}
+	marcador := marcadorProtecao(login.Body)
+	switch {
+	case login.StatusCode == http.StatusUnauthorized || login.StatusCode == http.StatusForbidden:
+		c.resultado, c.detalhe = hardeningPassou, fmt.Sprintf("protegido (status %d)", login.StatusCode)
+	case login.StatusCode == http.StatusOK && marcador != "":
+		c.resultado, c.detalhe = hardeningPassou, "protegido por WAF/captcha ("+marcador+")"
+	case login.StatusCode == http.StatusOK && strings.Contains(string(login.Body), "user_login"):
+		c.resultado, c.detalhe = hardeningFalhou, "formulário de login exposto"
+	default:
+		c.resultado, c.detalhe = hardeningNA, fmt.Sprintf("inconclusivo (status %d)", login.StatusCode)
+	}
+	return c
+}
+
+// controleWPAdmin reprova o wp-admin acessível ou que leva a um login público (seguindo os
+// redirecionamentos). Como no wp-login.php, só 401/403 contam como protegido.
+func controleWPAdmin(baseURL string) controleHardening {
+	c := controleHardening{nome: "wp-admin"}
+	admin, visitadas, err := seguirRedirecionamentos(baseURL + "/wp-admin/")
+	if err != nil {
+		c.resultado, c.detalhe = hardeningNA, "wp-admin inacessível"
+		return c
+	}
+	final := visitadas[len(visitadas)-1]
+	switch {
+	case admin.StatusCode == http.StatusUnauthorized || admin.StatusCode == http.StatusForbidden:
+		c.resultado, c.detalhe = hardeningPassou, fmt.Sprintf("protegido (status %d em %s)", admin.StatusCode, final)
+	case admin.StatusCode != http.StatusOK:
+		c.resultado, c.detalhe = hardeningNA, fmt.Sprintf("inconclusivo (status %d em %s)", admin.StatusCode, final)
+	case !strings.Contains(final, "wp-login.php"):
+		c.resultado, c.detalhe = hardeningFalhou, "acessível (status 200)"
+	case marcadorProtecao(admin.Body) != "":
+		c.resultado, c.detalhe = hardeningPassou, "login protegido por WAF/captcha ("+marcadorProtecao(admin.Body)+")"
+	default:
+		c.resultado, c.detalhe = hardeningFalhou, "redireciona para o login: "+final
+	}
+	return c
+}
@@ -0,0 +1,50 @@
+// internal\scanner\listagem.go
+package scanner
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// reHrefListagem captura os links de uma página de listagem de diretório.
+var reHrefListagem = regexp.MustCompile(`(?i)<a\s+[^>]*href\s*=\s*["']([^"'?#]+)["']`)
+
+// ehListagemDiretorio reconhece as páginas de autoindex mais comuns (Apache, Nginx, LiteSpeed, lighttpd, IIS).
+func ehListagemDiretorio(conteudo string) bool {
+	lower := strings.ToLower(conteudo)
+	return strings.Contains(lower, "<title>index of /") ||
+		strings.Contains(lower, "<h1>index of /") ||
+		strings.Contains(lower, "[to parent directory]")
+}
+
+// extrairEntradasListagem retorna os nomes das entradas da listagem (diretórios terminam em /),
+// ignorando links de ordenação e para o diretório pai.
+func extrairEntradasListagem(conteudo string) []string {
+	var entradas []string
+	vistos := make(map[string]bool)
+	for _, m := range reHrefListagem.FindAllStringSubmatch(conteudo, -1) {
+		href := m[1]
+		if strings.Contains(href, "://") || href == "/" || strings.HasPrefix(href, "..") {
+			continue
+		}
+		diretorio := strings.HasSuffix(href, "/")
+		// O IIS usa links absolutos (/caminho/arquivo); fica só o último segmento
+		partes := strings.Split(strings.TrimSuffix(href, "/"), "/")
+		nome := partes[len(partes)-1]
+		if dec, err := url.PathUnescape(nome); err == nil {
+			nome = dec
+		}
+		if nome == "" || nome == "." {
+			continue
+		}
+		if diretorio {
+			nome += "/"
+		}
+		if !vistos[nome] {
+			vistos[nome] = true
+			entradas = append(entradas, nome)
+		}
+	}
+	return entradas
+}
@@ -0,0 +1,124 @@
+// internal\scanner\listagemwp.go
+package scanner
+
+import (
+	"fmt"
+	"strings"
+
+	"Gowpscanner/internal/utils"
+)
+
+// diretoriosListagem são os diretórios do wp-content verificados quanto à listagem aberta.
+var diretoriosListagem = []string{
+	"wp-content/plugins/",
+	"wp-content/themes/",
+	"wp-content/uploads/",
+	"wp-content/backups/",
+}
+
+const (
+	// profundidadeUploads limita a descida nas subpastas de uploads (ano/mês/...).
+	profundidadeUploads = 3
+	// maxArquivosListagem limita quantos arquivos são enumerados por listagem.
+	maxArquivosListagem = 5000
+)
+
+// inventarioWP guarda os plugins e temas obtidos de listagens de diretório abertas.
+// Plugins/Temas ficam nil quando a listagem correspondente não está acessível.
+type inventarioWP struct {
+	Plugins []string
+	Temas   []string
+}
+
+// CheckListagemDiretorios procura listagens de diretório abertas no wp-content.
+// As listagens de plugins e temas são um inventário exato do que está instalado: os slugs
+// encontrados substituem a força bruta de pluginsCheck/themesCheck nas checagens de versão.
+// Os arquivos de uploads são enumerados em listagem/<host>.txt.
+func CheckListagemDiretorios(baseURL string) inventarioWP {
+	var inv inventarioWP
+	if !testarListagem {
+		return inv
+	}
+	destino := "listagem/" + hostSemWWW(baseURL) + ".txt"
+
+	for _, dir := range diretoriosListagem {
+		urlDir := fmt.Sprintf("%s/%s", baseURL, dir)
+		entradas, aberta := lerListagem(urlDir)
+		if !aberta {
+			continue
+		}
+		registro := fmt.Sprintf("%s - listagem de diretório aberta - %d entradas", urlDir, len(entradas))
+		utils.LogSave(registro, "listagem.txt")
+		utils.Warning("Listagem de diretório aberta: %s", registro)
+		utils.BeepAlert()
+
+		switch dir {
+		case "wp-content/plugins/":
+			inv.Plugins = slugsListados(entradas)
+			utils.Info("%d plugins listados em %s", len(inv.Plugins), urlDir)
+		case "wp-content/themes/":
+			inv.Temas = slugsListados(entradas)
+			utils.Info("%d temas listados em %s", len(inv.Temas), urlDir)
+		default:
+			total := 0
+			percorrerListagem(urlDir, dir, entradas, 0, &total, destino)
+			if total > 0 {
+				utils.Info("%d arquivos enumerados em %s (lista em retornos/%s)", total, urlDir, destino)
+			}
+		}
+	}
+	return inv
+}
+
+// lerListagem baixa o diretório e retorna suas entradas se for uma página de autoindex.
+func lerListagem(urlDir string) ([]string, bool) {
+	conteudo, err := utils.GetBody(urlDir)
+	if err != nil || !ehListagemDiretorio(conteudo) {
+		return nil, false
+	}
+	return extrairEntradasListagem(conteudo), true
+}
+
+// slugsListados retorna os nomes dos subdiretórios da listagem (os slugs de plugins ou temas).
+// Nunca retorna nil, para distinguir "listagem aberta e vazia" de "listagem fechada".
+func slugsListados(entradas []string) []string {
+	slugs := []string{}
+	for _, entrada := range entradas {
+		if strings.HasSuffix(entrada, "/") {
+			slugs = append(slugs, strings.TrimSuffix(entrada, "/"))
+		}
+	}
+	return slugs
+}
+
+// percorrerListagem registra os arquivos da listagem e desce nas subpastas até profundidadeUploads.
+// Backups listados são validados como em CheckArquivosBackup; scripts PHP em uploads são alertados.
+func percorrerListagem(urlDir, caminho string, entradas []string, nivel int, total *int, destino string) {
+	for _, entrada := range entradas {
+		if *total >= maxArquivosListagem {
+			return
+		}
+		if strings.HasSuffix(entrada, "/") {
+			if nivel+1 > profundidadeUploads {
+				continue
+			}
+			if sub, aberta := lerListagem(urlDir + entrada); aberta {
+				percorrerListagem(urlDir+entrada, caminho+entrada, sub, nivel+1, total, destino)
+			}
+			continue
+		}
+
+		*total++
+		utils.LogSave(caminho+entrada, destino)
+		lower := strings.ToLower(entrada)
+		switch {
+		case temExtensaoBackup(entrada):
+			verificarArquivoBackup(urlDir + entrada)
+		case strings.HasPrefix(caminho, "wp-content/uploads/") && (strings.HasSuffix(lower, ".php") || strings.Contains(lower, ".php.")):
+			registro := fmt.Sprintf("%s%s - script PHP na pasta de uploads", urlDir, entrada)
+			utils.LogSave(registro, "listagem.txt")
+			utils.Warning("Possível shell: %s", registro)
+			utils.BeepAlert()
+		}
+	}
+}
@@ -0,0 +1,203 @@
+// internal\scanner\multisite.go
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"Gowpscanner/internal/utils"
+	"Gowpscanner/internal/wpdetect"
+)
+
+// maxSitesRelacionados limita quantos sites relacionados são escaneados por alvo.
+const maxSitesRelacionados = 20
+
+var (
+	// reRaizLink captura a raiz de uma instalação a partir de links para wp-content/wp-includes.
+	reRaizLink   = regexp.MustCompile(`(?i)(?:href|src)\s*=\s*["']((?:https?:)?//[^/"'\s]+(?:/[^"'\s]*?)?|/[^"'\s]*?)/wp-(?:content|includes)/`)
+	reLocSitemap = regexp.MustCompile(`<loc>\s*([^<\s]+)\s*</loc>`)
+
+	// sitesEscaneados evita escanear a mesma instalação mais de uma vez.
+	sitesEscaneados sync.Map
+)
+
+// CheckSitesRelacionados detecta se a instalação faz parte de uma rede multisite (wp-signup.php,
+// wp-content/blogs.dir) e procura outras instalações do mesmo domínio (sitemap da rede e links
+// para wp-content/wp-includes em outros subdiretórios ou subdomínios). Cada site encontrado é
+// escaneado como um alvo próprio e a relação com o site principal é salva em multisite.txt.
+// Retorna os hosts de todos os candidatos acessados, para que o cache deles seja limpo junto com o do alvo.
+func CheckSitesRelacionados(baseURL, dominio string) []string {
+	if !testarMultisite {
+		return nil
+	}
+	sitesEscaneados.Store(normalizarSite(baseURL), true)
+
+	multisite, evidencia := detectarMultisite(baseURL)
+	if multisite {
+		registro := fmt.Sprintf("%s - rede multisite - evidência: %s", baseURL, evidencia)
+		utils.LogSave(registro, "multisite.txt")
+		utils.Info("Multisite: %s", registro)
+	}
+
+	candidatos := candidatosSitesRelacionados(baseURL)
+	var hosts []string
+	escaneados := 0
+	for _, candidato := range candidatos {
+		if escaneados >= maxSitesRelacionados {
+			utils.Info("Limite de %d sites relacionados atingido em %s", maxSitesRelacionados, baseURL)
+			break
+		}
+		if _, existe := sitesEscaneados.LoadOrStore(normalizarSite(candidato.url), true); existe {
+			continue
+		}
+		hosts = append(hosts, hostDoAlvo(candidato.url))
+		body, err := utils.GetBody(candidato.url + "/")
+		if err != nil || !wpdetect.TemSinaisWordPress(body) {
+			continue
+		}
+		relacao := "instalação WordPress em " + candidato.tipo
+		if multisite {
+			relacao = candidato.tipo + " da rede multisite"
+		}
+		registro := fmt.Sprintf("%s -> %s - %s - origem: %s", baseURL, candidato.url, relacao, candidato.origem)
+		utils.LogSave(registro, "multisite.txt")
+		utils.Ok("Site relacionado: %s", registro)
+
+		escaneados++
+		escanearWordPress(candidato.url, dominio)
+	}
+	return hosts
+}
+
+// detectarMultisite procura os sinais de uma rede multisite e retorna a evidência encontrada.
+func detectarMultisite(baseURL string) (bool, string) {
+	var evidencias []string
+
+	// Numa instalação simples o wp-signup.php redireciona para wp-login.php?action=register
+	if resp, err := utils.Buscar(baseURL + "/wp-signup.php"); err == nil && resp.StatusCode == http.StatusOK {
+		if strings.Contains(string(resp.Body), "signup-content") {
+			evidencias = append(evidencias, "wp-signup.php")
+		}
+	}
+	// blogs.dir é a pasta de uploads dos sites da rede nas instalações antigas (antes do 3.5), com uma
+	// subpasta por ID de site. Só a listagem com essas subpastas é evidência: muitos servidores respondem
+	// 403 a qualquer diretório em wp-content, então o 403 apenas complementa outra evidência.
+	if resp, err := utils.Buscar(baseURL + "/wp-content/blogs.dir/"); err == nil {
+		conteudo := string(resp.Body)
+		switch {
+		case resp.StatusCode == http.StatusOK && ehListagemDiretorio(conteudo):
+			if ids := idsBlogsDir(extrairEntradasListagem(conteudo)); len(ids) > 0 {
+				evidencias = append(evidencias, fmt.Sprintf("wp-content/blogs.dir listado (sites %s)", strings.Join(ids, ", ")))
+			}
+		case resp.StatusCode == http.StatusForbidden && len(evidencias) > 0:
+			evidencias = append(evidencias, "wp-content/blogs.dir (status 403)")
+		}
+	}
+	return len(evidencias) > 0, strings.Join(evidencias, ", ")
+}
+
+// idsBlogsDir retorna as subpastas numéricas (IDs de sites) de uma listagem de blogs.dir.
+func idsBlogsDir(entradas []string) []string {
+	var ids []string
+	for _, entrada := range entradas {
+		nome, diretorio := strings.CutSuffix(entrada, "/")
+		if diretorio && nome != "" && strings.Trim(nome, "0123456789") == "" {
+			ids = append(ids, nome)
+		}
+	}
+	return ids
+}
+
+// siteCandidato é uma possível instalação relacionada, com o tipo (subdiretório/subdomínio) e a origem.
+type siteCandidato struct {
+	url    string
+	tipo   string
+	origem string
+}
+
+// candidatosSitesRelacionados reúne as raízes de instalações citadas no sitemap e nos links da página inicial.
+func candidatosSitesRelacionados(baseURL string) []siteCandidato {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil
+	}
+	var candidatos []siteCandidato
+	vistos := map[string]bool{normalizarSite(baseURL): true}
+	adicionar := func(raiz, origem string) {
+		u, err := base.Parse(raiz)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return
+		}
+		u.RawQuery, u.Fragment = "", ""
+		u.Path = strings.TrimSuffix(u.Path, "/")
+		tipo := relacaoHost(base.Hostname(), u.Hostname())
+		if tipo == "" {
+			return
+		}
+		if tipo == "subdomínio" && u.Path != "" {
+			tipo = "subdiretório de subdomínio"
+		}
+		chave := normalizarSite(u.String())
+		if vistos[chave] {
+			return
+		}
+		vistos[chave] = true
+		candidatos = append(candidatos, siteCandidato{url: u.String(), tipo: tipo, origem: origem})
+	}
+
+	// Sitemap da rede: em cada site o índice aponta para /<site>/wp-sitemap-*.xml
+	for _, sitemap := range []string{"wp-sitemap.xml", "sitemap_index.xml", "sitemap.xml"} {
+		conteudo, err := utils.GetBody(fmt.Sprintf("%s/%s", baseURL, sitemap))
+		if err != nil {
+			continue
+		}
+		for _, m := range reLocSitemap.FindAllStringSubmatch(conteudo, -1) {
+			if raiz := raizSitemap(m[1]); raiz != "" {
+				adicionar(raiz, sitemap)
+			}
+		}
+	}
+
+	// Links para wp-content/wp-includes de outras instalações
+	if body, err := utils.GetBody(baseURL + "/"); err == nil {
+		for _, m := range reRaizLink.FindAllStringSubmatch(body, -1) {
+			adicionar(m[1], "links da página inicial")
+		}
+	}
+	return candidatos
+}
+
+// raizSitemap retorna a raiz do site dono de um sitemap (tudo antes de /wp-sitemap ou /sitemap).
+func raizSitemap(loc string) string {
+	for _, marcador := range []string{"/wp-sitemap", "/sitemap"} {
+		if idx := strings.Index(loc, marcador); idx != -1 {
+			return loc[:idx]
+		}
+	}
+	return ""
+}
+
+// relacaoHost classifica o host do candidato em relação ao host principal: "subdiretório" (mesmo host,
+// com ou sem www.), "subdomínio" (subdomínio do mesmo domínio) ou "" para hosts de outros domínios.
+func relacaoHost(principal, candidato string) string {
+	principal = strings.TrimPrefix(strings.ToLower(principal), "www.")
+	candidato = strings.ToLower(candidato)
+	switch {
+	case strings.TrimPrefix(candidato, "www.") == principal:
+		return "subdiretório"
+	case strings.HasSuffix(candidato, "."+principal):
+		return "subdomínio"
+	}
+	return ""
+}
+
+// normalizarSite identifica uma instalação independente do esquema, do www. e da barra final.
+func normalizarSite(site string) string {
+	site = strings.ToLower(strings.TrimSuffix(site, "/"))
+	site = strings.TrimPrefix(strings.TrimPrefix(site, "https://"), "http://")
+	return strings.TrimPrefix(site, "www.")
+}
@@ -23,20 +23,26 @@ var (
 	dfErr             error
 )
 
-// CheckPlugins faz a varredura de plugins vulneráveis
-func CheckPlugins(baseURL, dominio string) {
+// CheckPlugins faz a varredura de plugins vulneráveis.
+// Se listados não for nil (listagem de wp-content/plugins/ aberta), verifica apenas esses slugs
+// em vez da força bruta de pluginsCheck.
+func CheckPlugins(baseURL, dominio string, listados []string) {
 	var contador int
-	//proteção contra sites que retornam plugins falsos
-	version, _ := extrairVersaoPlugins(baseURL, "plugin-nao-existe")
-	if version != "" {
-		utils.Warning("Plugin inexistente encontrado em %s", dominio)
-		return
+	slugs := listados
+	if slugs == nil {
+		//proteção contra sites que retornam plugins falsos
+		version, _ := extrairVersaoPlugins(baseURL, "plugin-nao-existe")
+		if version != "" {
+			utils.Warning("Plugin inexistente encontrado em %s", dominio)
+			return
+		}
+		slugs = pluginsCheck
 	}
-	for _, slug := range pluginsCheck {
+	for _, slug := range slugs {
 		contador++
 		//caso o contador seja multiplo de 100, exibe mensagem
 		if contador%100 == 0 {
-			utils.Info("Verificando Plugins %s -  %d/%d", baseURL, contador, len(pluginsCheck))
+			utils.Info("Verificando Plugins %s -  %d/%d", baseURL, contador, len(slugs))
 		}
 		version, urlReadme := extrairVersaoPlugins(baseURL, slug)
 		if version != "" {
@@ -78,10 +84,12 @@ func extrairVersaoPlugins(baseURL, pluginSlug string) (string, string) {
 	readmeFilename := GetPluginReadmePath(pluginSlug)
 	urlReadme := fmt.Sprintf("%s/wp-content/plugins/%s/%s", baseURL, pluginSlug, readmeFilename)
 
-	conteudo, err := utils.GetBody(urlReadme)
+	conteudo, err := utils.GetBodyLimitado(urlReadme, utils.LimiteBody("plugins"))
 	if err != nil {
 		return "", urlReadme
 	}
+	// Erros do PHP podem vazar caminhos absolutos do servidor
+	registrarErroPHP(urlReadme, conteudo)
 	// Se conter tags HTML ou erros comuns, ignoramos
 	if strings.Contains(conteudo, "<head") ||
 		strings.Contains(conteudo, "<body") ||
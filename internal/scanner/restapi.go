@@ -0,0 +1,160 @@
+// internal\scanner\restapi.go
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"Gowpscanner/internal/utils"
+)
+
+// namespacesCore são os namespaces da REST API do próprio WordPress; os demais pertencem a plugins.
+var namespacesCore = map[string]bool{
+	"oembed/1.0":         true,
+	"wp/v2":              true,
+	"wp-site-health/v1":  true,
+	"wp-block-editor/v1": true,
+	"wp-abilities/v1":    true,
+}
+
+var (
+	reMetodoXMLRPC  = regexp.MustCompile(`<string>([\w.]+)</string>`)
+	reVersaoReadme  = regexp.MustCompile(`(?i)<br\s*/?>\s*Vers(?:ion|ão)\s+(\d+\.\d+(?:\.\d+)?)`)
+	corpoListMethod = []byte(`<?xml version="1.0"?><methodCall><methodName>system.listMethods</methodName><params></params></methodCall>`)
+)
+
+// CheckRestAPI verifica a exposição da REST API (/wp-json/ e listagem de autores), do xmlrpc.php,
+// do wp-cron.php e do readme.html. Cada item acessível é um achado de configuração, salvo com a evidência.
+func CheckRestAPI(baseURL string) {
+	if !testarRestAPI {
+		return
+	}
+	verificarNamespacesREST(baseURL)
+	verificarUsuariosREST(baseURL)
+	verificarXMLRPC(baseURL)
+	verificarWPCron(baseURL)
+	verificarReadmeHTML(baseURL)
+}
+
+// registrarConfiguracao salva um achado de configuração com a evidência que o comprova.
+func registrarConfiguracao(urlRef, achado, evidencia string) {
+	registro := fmt.Sprintf("%s - %s - evidência: %s", urlRef, achado, evidencia)
+	utils.LogSave(registro, "configuracao-wp.txt")
+	utils.Warning("Configuração: %s", registro)
+}
+
+// verificarNamespacesREST lista os namespaces da REST API; os que não são do core revelam plugins instalados.
+func verificarNamespacesREST(baseURL string) {
+	for _, path := range []string{"wp-json/", "?rest_route=/"} {
+		urlAPI := fmt.Sprintf("%s/%s", baseURL, path)
+		conteudo, err := utils.GetBody(urlAPI)
+		if err != nil {
+			continue
+		}
+		var indice struct {
+			Namespaces []string `json:"namespaces"`
+		}
+		if json.Unmarshal([]byte(conteudo), &indice) != nil || len(indice.Namespaces) == 0 {
+			continue
+		}
+		var plugins []string
+		for _, ns := range indice.Namespaces {
+			if !namespacesCore[ns] {
+				plugins = append(plugins, ns)
+			}
+		}
+		evidencia := fmt.Sprintf("%d namespaces", len(indice.Namespaces))
+		if len(plugins) > 0 {
+			evidencia += " - de plugins: " + strings.Join(plugins, ", ")
+		}
+		registrarConfiguracao(urlAPI, "REST API pública", evidencia)
+		return
+	}
+}
+
+// verificarUsuariosREST verifica se /wp-json/wp/v2/users lista os autores sem autenticação.
+func verificarUsuariosREST(baseURL string) {
+	for _, path := range []string{"wp-json/wp/v2/users", "?rest_route=/wp/v2/users"} {
+		urlUsuarios := fmt.Sprintf("%s/%s", baseURL, path)
+		conteudo, err := utils.GetBody(urlUsuarios)
+		if err != nil {
+			continue
+		}
+		var usuarios []json.RawMessage
+		if json.Unmarshal([]byte(conteudo), &usuarios) != nil || len(usuarios) == 0 {
+			continue
+		}
+		// Os nomes só são enumerados por CheckUsuarios (opt-in); aqui basta a quantidade
+		registrarConfiguracao(urlUsuarios, "listagem de autores pela REST API", fmt.Sprintf("%d usuários listados", len(usuarios)))
+		return
+	}
+}
+
+// verificarXMLRPC verifica se o xmlrpc.php responde e quais métodos expõe (system.listMethods).
+// pingback.ping e system.multicall são destacados: permitem SSRF/DDoS refletido e força bruta em lote.
+func verificarXMLRPC(baseURL string) {
+	urlXMLRPC := fmt.Sprintf("%s/xmlrpc.php", baseURL)
+	resp, err := utils.Enviar(urlXMLRPC, "text/xml", corpoListMethod)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return
+	}
+	conteudo := string(resp.Body)
+	if !strings.Contains(conteudo, "<methodResponse>") {
+		return
+	}
+	metodos := reMetodoXMLRPC.FindAllStringSubmatch(conteudo, -1)
+	var destaques []string
+	for _, m := range metodos {
+		if m[1] == "pingback.ping" || m[1] == "system.multicall" {
+			destaques = append(destaques, m[1])
+		}
+	}
+	evidencia := fmt.Sprintf("system.listMethods retornou %d métodos", len(metodos))
+	if len(destaques) > 0 {
+		evidencia += " - incluindo " + strings.Join(destaques, ", ")
+	}
+	registrarConfiguracao(urlXMLRPC, "XML-RPC habilitado", evidencia)
+
+	// O cabeçalho X-Pingback anuncia o endpoint de pingback na página inicial
+	if home, err := utils.Buscar(baseURL + "/"); err == nil {
+		if pingback := home.Header.Get("X-Pingback"); pingback != "" {
+			registrarConfiguracao(baseURL+"/", "pingback anunciado", "X-Pingback: "+pingback)
+		}
+	}
+}
+
+// verificarWPCron verifica se o wp-cron.php pode ser disparado externamente. Qualquer requisição ao
+// wp-cron.php (inclusive HEAD) executa os agendamentos pendentes do site, por isso a checagem é
+// opt-in (TESTAR_WPCRON) e usa HEAD, para ao menos não baixar nada.
+func verificarWPCron(baseURL string) {
+	if !testarWPCron {
+		return
+	}
+	urlCron := fmt.Sprintf("%s/wp-cron.php", baseURL)
+	resp, err := utils.BuscarHead(urlCron)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return
+	}
+	// O wp-cron.php responde 200 sem conteúdo; um Content-Length indica página de erro ou inicial
+	if resp.Tamanho > 0 {
+		return
+	}
+	registrarConfiguracao(urlCron, "wp-cron.php acessível externamente", fmt.Sprintf("HEAD status %d, sem conteúdo", resp.StatusCode))
+}
+
+// verificarReadmeHTML verifica se o readme.html padrão do WordPress está publicado.
+func verificarReadmeHTML(baseURL string) {
+	urlReadme := fmt.Sprintf("%s/readme.html", baseURL)
+	conteudo, err := utils.GetBody(urlReadme)
+	if err != nil || !strings.Contains(conteudo, "WordPress") || !strings.Contains(conteudo, "ReadMe") {
+		return
+	}
+	evidencia := "readme.html padrão do WordPress"
+	if m := reVersaoReadme.FindStringSubmatch(conteudo); m != nil {
+		evidencia += " - versão " + m[1]
+	}
+	registrarConfiguracao(urlReadme, "readme.html exposto", evidencia)
+}
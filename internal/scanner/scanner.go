@@ -3,6 +3,7 @@ package scanner
 
 import (
 	"Gowpscanner/internal/utils"
+	"Gowpscanner/internal/wpdetect"
 	"Gowpscanner/pkg/update"
 	"bufio"
 	"fmt"
@@ -31,6 +32,32 @@ var (
 	testarYaml = true
 	// Testar timthumbs?
 	testarTimthumbs = true
+	// Testar exportações de banco de dados?
+	testarDBExports = true
+	// Testar arquivos de backup do site?
+	testarArquivosBackup = true
+	// Testar metadados de controle de versão (.git, .svn, .hg)?
+	testarVCS = true
+	// Testar debug.log e páginas de erro do PHP?
+	testarDebugLog = true
+	// Testar listagem de diretórios aberta no wp-content?
+	testarListagem = true
+	// Testar REST API, XML-RPC, wp-cron.php e readme.html?
+	testarRestAPI = true
+	// Testar o wp-cron.php? (opt-in: a requisição dispara os agendamentos pendentes do site)
+	testarWPCron = false
+	// Enumerar usuários do WordPress? (opt-in, apenas em avaliações autorizadas)
+	testarUsuarios = false
+	// Detectar redes multisite e outras instalações do mesmo domínio?
+	testarMultisite = true
+	// Gerar o relatório de hardening (cabeçalhos de segurança, cookies, login)?
+	testarHardening = true
+	// Procurar segredos genéricos (alta entropia em chaves sensíveis)?
+	testarSegredosGenericos = true
+	// Modo offline: nunca contatar serviços de terceiros (DigitalOcean, Firebase)?
+	modoOffline = true
+	// Exigir o arquivo de autorização assinado antes de escanear?
+	autorizacaoObrigatoria = true
 )
 
 // Códigos ANSI para cores
@@ -80,17 +107,145 @@ func init() {
 	if val := os.Getenv("TESTAR_YAML"); val != "" {
 		testarYaml = strings.ToLower(val) == "true"
 	}
+	if val := os.Getenv("TESTAR_DBEXPORTS"); val != "" {
+		testarDBExports = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("TESTAR_ARQUIVOS_BACKUP"); val != "" {
+		testarArquivosBackup = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("TESTAR_VCS"); val != "" {
+		testarVCS = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("TESTAR_DEBUGLOG"); val != "" {
+		testarDebugLog = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("TESTAR_LISTAGEM"); val != "" {
+		testarListagem = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("TESTAR_REST_API"); val != "" {
+		testarRestAPI = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("TESTAR_WPCRON"); val != "" {
+		testarWPCron = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("TESTAR_USUARIOS"); val != "" {
+		testarUsuarios = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("USUARIOS_MAX"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n > 0 {
+			maxAutores = n
+		}
+	}
+	if val := os.Getenv("TESTAR_MULTISITE"); val != "" {
+		testarMultisite = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("TESTAR_HARDENING"); val != "" {
+		testarHardening = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("TESTAR_SEGREDOS_GENERICOS"); val != "" {
+		testarSegredosGenericos = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("MODO_OFFLINE"); val != "" {
+		modoOffline = strings.ToLower(val) == "true"
+	}
+	wpdetect.ConfigurarModoOffline(modoOffline)
+	if val := os.Getenv("AUTORIZACAO_OBRIGATORIA"); val != "" {
+		autorizacaoObrigatoria = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("LISTAR_ARQUIVOS_GIT"); val != "" {
+		listarArquivosGit = strings.ToLower(val) == "true"
+	}
+
+	// Redação dos segredos na saída (REDIGIR_SEGREDOS) e arquivo de evidências cifrado (EVIDENCIAS_CHAVE,
+	// usada também para cifrar o cassete de HTTP_MODO=gravar)
+	if err := utils.ConfigurarRedacao(); err != nil {
+		utils.Error("Erro ao configurar a redação de segredos: %v", err)
+		os.Exit(1)
+	}
+
+	// Configura o cliente HTTP (gravação/reprodução de tráfego etc.)
+	if err := utils.ConfigurarHTTP(); err != nil {
+		utils.Error("Erro ao configurar o cliente HTTP: %v", err)
+		os.Exit(1)
+	}
+
+	// Carrega as regras de detecção de segredos (padrão + arquivo do usuário em TOKENS_REGRAS, YAML ou
+	// configuração do gitleaks em .toml). TOKENS_PADRAO=false usa somente as regras do usuário.
+	regrasPadrao := "paths/tokens.yml"
+	if strings.ToLower(os.Getenv("TOKENS_PADRAO")) == "false" {
+		regrasPadrao = ""
+	}
+	if err := wpdetect.CarregarRegrasTokens(regrasPadrao, os.Getenv("TOKENS_REGRAS")); err != nil {
+		utils.Error("Erro ao carregar as regras de tokens: %v", err)
+		os.Exit(1)
+	}
+
+	// Limites do detector genérico de segredos e placeholders ignorados (usados também na análise dos
+	// arquivos YAML, por isso são carregados mesmo com TESTAR_SEGREDOS_GENERICOS=false)
+	entropia, entropiaSenha, tamanhoMinimo := 3.5, 2.5, 6
+	if val := os.Getenv("ENTROPIA_MINIMA"); val != "" {
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			entropia = f
+		}
+	}
+	if val := os.Getenv("ENTROPIA_MINIMA_SENHA"); val != "" {
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			entropiaSenha = f
+		}
+	}
+	if val := os.Getenv("SEGREDO_TAMANHO_MINIMO"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil {
+			tamanhoMinimo = n
+		}
+	}
+	placeholders := utils.CarregarListas("paths/placeholders.txt")
+	if err := wpdetect.ConfigurarSegredosGenericos(entropia, entropiaSenha, tamanhoMinimo, placeholders); err != nil {
+		utils.Error("Erro ao configurar o detector genérico de segredos: %v", err)
+		os.Exit(1)
+	}
+
+	// Decodificação de conteúdos (base64, strings escapadas, gzip/zip) antes da busca de segredos
+	profundidade, limiteDecodificacao := 2, int64(2*1024*1024)
+	if val := os.Getenv("DECODIFICAR_PROFUNDIDADE"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n >= 0 {
+			profundidade = n
+		}
+	}
+	if val := os.Getenv("DECODIFICAR_MAX_BYTES"); val != "" {
+		if n, err := strconv.ParseInt(val, 10, 64); err == nil && n > 0 {
+			limiteDecodificacao = n
+		}
+	}
+	wpdetect.ConfigurarDecodificacao(profundidade, limiteDecodificacao)
 
 	// Exemplo:
 	//configList = utils.CarregarListas("database/config_backups.txt")
 	configList = utils.CarregarListas("paths/configs.txt")
-	dbExportsList = utils.CarregarListas("database/db_exports.txt")
+	if testarDBExports {
+		dbExportsList = utils.CarregarListas("database/db_exports.txt")
+	}
+	if testarArquivosBackup {
+		backupList = utils.CarregarListas("paths/backups.txt")
+	}
+	if testarDebugLog {
+		logsList = utils.CarregarListas("paths/logs.txt")
+		fpdList = utils.CarregarListas("paths/fpd.txt")
+	}
 	timthumbPaths = utils.CarregarListas("database/timthumbs-v3.txt")
 	if testarShells {
 		shellList = utils.CarregarListas("paths/shells.txt")
 	}
 	if testarYaml {
 		yamlList = utils.CarregarListas("paths/yamls.txt")
+		// Chaves sensíveis e assinaturas dos tipos de arquivo YAML (YAML_ASSINATURAS substitui o arquivo padrão)
+		assinaturas := "paths/yaml_assinaturas.yml"
+		if val := os.Getenv("YAML_ASSINATURAS"); val != "" {
+			assinaturas = val
+		}
+		if err := wpdetect.CarregarAssinaturasYaml(assinaturas); err != nil {
+			utils.Error("Erro ao carregar as assinaturas YAML: %v", err)
+			os.Exit(1)
+		}
 	}
 
 	if testarEnv {
@@ -199,11 +354,19 @@ func printTable() {
 	fmt.Println(subSeparator)
 	fmt.Printf("| %-35s | %-12s |\n", "Quantidade de Checagens Únicas:", "")
 	fmt.Println(subSeparator)
+	fmt.Printf("| %-35s | %-12d |\n", "Regras de Tokens", wpdetect.QuantidadeRegrasTokens())
+	fmt.Printf("| %-35s | %-12t |\n", "Modo Offline", modoOffline)
+	fmt.Printf("| %-35s | %-12t |\n", "Escopo Restrito", utils.EscopoAtivo())
+	fmt.Println(subSeparator)
 	fmt.Printf("| %-35s | %-12d |\n", "Plugins", len(pluginsCheck))
 	fmt.Printf("| %-35s | %-12d |\n", "Themes", len(themesCheck))
 	fmt.Printf("| %-35s | %-12d |\n", "Shells", len(shellList))
 	fmt.Printf("| %-35s | %-12d |\n", ".Envs", len(envList))
 	fmt.Printf("| %-35s | %-12d |\n", "Yamls", len(yamlList))
+	fmt.Printf("| %-35s | %-12d |\n", "Assinaturas YAML", wpdetect.QuantidadeAssinaturasYaml())
+	fmt.Printf("| %-35s | %-12d |\n", "DB Exports", len(dbExportsList))
+	fmt.Printf("| %-35s | %-12d |\n", "Arquivos de Backup", len(backupList))
+	fmt.Printf("| %-35s | %-12d |\n", "Logs / Erros PHP", len(logsList)+len(fpdList))
 	fmt.Println(separator)
 }
 
@@ -216,8 +379,26 @@ func existsPlugin(pluginList []PluginVulneravel, slug, description string) bool
 	return false
 }
 
-// Run lê o arquivo de domínios e coordena o processo de escaneamento
+// Run lê o arquivo de domínios e coordena o processo de escaneamento.
+// Com AUTORIZACAO_OBRIGATORIA (padrão) o arquivo de autorização assinado é conferido antes de
+// qualquer linha da entrada, e apenas os domínios autorizados são escaneados.
 func Run(domainsFile string) error {
+	if autorizacaoObrigatoria {
+		caminho := os.Getenv("AUTORIZACAO_ARQUIVO")
+		if caminho == "" {
+			caminho = "autorizacao.yml"
+		}
+		// A chave privada do aprovador não pode estar no ambiente de quem executa o scan
+		if os.Getenv("AUTORIZACAO_CHAVE_PRIVADA") != "" {
+			return fmt.Errorf("AUTORIZACAO_CHAVE_PRIVADA definida no ambiente do scanner: remova-a do .env (a chave fica só com o aprovador)")
+		}
+		aut, aprovador, err := utils.CarregarAutorizacao(caminho)
+		if err != nil {
+			return fmt.Errorf("autorização inválida (AUTORIZACAO_OBRIGATORIA=false desativa a exigência): %w", err)
+		}
+		utils.Ok("Autorização do engajamento %s aprovada por %s, válida até %s (%d domínios)", aut.Engajamento, aprovador, aut.Expira, len(aut.Dominios))
+	}
+
 	file, err := os.Open(domainsFile)
 	if err != nil {
 		return fmt.Errorf("erro ao abrir %s: %w", domainsFile, err)
@@ -238,6 +419,11 @@ func Run(domainsFile string) error {
 		if re.MatchString(domainsplit[0]) {
 			continue
 		}
+		if err := utils.DominioAutorizado(hostDoAlvo(domain)); err != nil {
+			utils.Error("%s ignorado: %v", domain, err)
+			utils.LogSave(domain, "nao-autorizados.txt")
+			continue
+		}
 
 		limitCh <- struct{}{}
 		wg.Add(1)
@@ -250,5 +436,7 @@ func Run(domainsFile string) error {
 	}
 
 	wg.Wait()
+	// Relatório consolidado dos segredos (um achado por segredo, com todos os locais)
+	wpdetect.SalvarAchadosTokens()
 	return scanner.Err()
 }
@@ -11,14 +11,20 @@ import (
 var themesList []PluginVulneravel
 var themesCheck []string
 
-// CheckThemes faz a varredura de temas vulneráveis
-func CheckThemes(baseURL, dominio string) {
+// CheckThemes faz a varredura de temas vulneráveis.
+// Se listados não for nil (listagem de wp-content/themes/ aberta), verifica apenas esses slugs
+// em vez da força bruta de themesCheck.
+func CheckThemes(baseURL, dominio string, listados []string) {
 	var contador int
-	for _, slug := range themesCheck {
+	slugs := listados
+	if slugs == nil {
+		slugs = themesCheck
+	}
+	for _, slug := range slugs {
 		contador++
 		//caso o contador seja multiplo de 100, exibe mensagem
 		if contador%100 == 0 {
-			utils.Info("Verificando Themes %s -  %d/%d", baseURL, contador, len(themesCheck))
+			utils.Info("Verificando Themes %s -  %d/%d", baseURL, contador, len(slugs))
 		}
 		version := extrairVersaoThemes(baseURL, slug)
 		if version != "" {
@@ -60,10 +66,12 @@ func salvaRetornoThemes(themeSlug, urlRef, versao, descricao string) {
 func extrairVersaoThemes(baseURL, themeSlug string) string {
 	urlStyle := fmt.Sprintf("%s/wp-content/themes/%s/style.css", baseURL, themeSlug)
 
-	conteudo, err := utils.GetBody(urlStyle)
+	conteudo, err := utils.GetBodyLimitado(urlStyle, utils.LimiteBody("themes"))
 	if err != nil {
 		return ""
 	}
+	// Erros do PHP podem vazar caminhos absolutos do servidor
+	registrarErroPHP(urlStyle, conteudo)
 	if strings.Contains(conteudo, "<head") ||
 		strings.Contains(conteudo, "<body") ||
 		strings.Contains(conteudo, "Invalid Request") ||
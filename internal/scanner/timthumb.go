@@ -9,6 +9,8 @@ import (
 	"strings"
 	"time"
 
+	"Gowpscanner/internal/utils"
+
 	browser "github.com/EDDYCJY/fake-useragent"
 )
 
@@ -19,9 +21,9 @@ var timthumbPaths []string
 func detectTimThumb(url string) (isFound bool, err error) {
 	client := &http.Client{
 		Timeout: 10 * time.Second,
-		Transport: &http.Transport{
+		Transport: utils.EnvolverTransporte(&http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
+		}),
 	}
 
 	req, errReq := http.NewRequest("GET", url, nil)
@@ -37,7 +39,8 @@ func detectTimThumb(url string) (isFound bool, err error) {
 	}
 	defer resp.Body.Close()
 
-	body, errRead := io.ReadAll(resp.Body)
+	// Leitura limitada como nas demais checagens (MAX_BODY_TIMTHUMB ou MAX_BODY)
+	body, errRead := io.ReadAll(io.LimitReader(resp.Body, utils.LimiteBody("timthumb")))
 	if errRead != nil {
 		return false, fmt.Errorf("erro ao ler o body: %v", errRead)
 	}
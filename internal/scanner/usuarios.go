@@ -0,0 +1,150 @@
+// internal\scanner\usuarios.go
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"Gowpscanner/internal/utils"
+)
+
+// maxAutores é quantos IDs são testados em ?author=N (USUARIOS_MAX).
+var maxAutores = 10
+
+var (
+	reSlugAutor     = regexp.MustCompile(`/author/([^/?#"']+)`)
+	reClasseAutor   = regexp.MustCompile(`<body[^>]*class="[^"]*\bauthor-([\w%.-]+)\s+author-\d+`)
+	reCriadorRSS    = regexp.MustCompile(`<dc:creator>\s*(?:<!\[CDATA\[)?\s*([^<\]]+?)\s*(?:\]\]>)?\s*</dc:creator>`)
+	tecnicasOrdenar = []string{"author-redirect", "author-página", "rest-api", "oembed", "rss"}
+)
+
+// usuariosEncontrados agrupa os usuários por nome, com as técnicas que revelaram cada um.
+type usuariosEncontrados map[string]map[string]bool
+
+// adicionar registra um usuário (slug ou nome de exibição) revelado por uma técnica.
+func (u usuariosEncontrados) adicionar(nome, tecnica string) {
+	if dec, err := url.PathUnescape(nome); err == nil {
+		nome = dec
+	}
+	nome = strings.TrimSpace(nome)
+	if nome == "" {
+		return
+	}
+	if u[nome] == nil {
+		u[nome] = make(map[string]bool)
+	}
+	u[nome][tecnica] = true
+}
+
+// CheckUsuarios enumera os autores do site (opt-in, TESTAR_USUARIOS=true) por ?author=N, pela REST API,
+// pelo oEmbed e pelo dc:creator do RSS. Serve para confirmar se as mitigações de enumeração funcionam:
+// cada usuário é salvo em usuarios.txt uma única vez, com as técnicas que o revelaram.
+func CheckUsuarios(baseURL string) {
+	if !testarUsuarios {
+		return
+	}
+	usuarios := make(usuariosEncontrados)
+	enumerarPorAutor(baseURL, usuarios)
+	enumerarPorREST(baseURL, usuarios)
+	enumerarPorOEmbed(baseURL, usuarios)
+	enumerarPorRSS(baseURL, usuarios)
+
+	if len(usuarios) == 0 {
+		utils.Ok("Nenhum usuário enumerado em %s", baseURL)
+		return
+	}
+	nomes := make([]string, 0, len(usuarios))
+	for nome := range usuarios {
+		nomes = append(nomes, nome)
+	}
+	sort.Strings(nomes)
+	for _, nome := range nomes {
+		var tecnicas []string
+		for _, t := range tecnicasOrdenar {
+			if usuarios[nome][t] {
+				tecnicas = append(tecnicas, t)
+			}
+		}
+		registro := fmt.Sprintf("%s - usuário: %s - técnicas: %s", baseURL, nome, strings.Join(tecnicas, ", "))
+		utils.LogSave(registro, "usuarios.txt")
+		utils.Warning("Usuário enumerado: %s", registro)
+	}
+}
+
+// enumerarPorAutor testa ?author=1..N: o redirecionamento para /author/<slug>/ ou a classe
+// author-<slug> no <body> da página do autor revelam o slug.
+func enumerarPorAutor(baseURL string, usuarios usuariosEncontrados) {
+	for id := 1; id <= maxAutores; id++ {
+		resp, err := utils.Buscar(fmt.Sprintf("%s/?author=%d", baseURL, id))
+		if err != nil {
+			continue
+		}
+		switch {
+		case resp.StatusCode >= 300 && resp.StatusCode < 400:
+			if m := reSlugAutor.FindStringSubmatch(resp.Header.Get("Location")); m != nil {
+				usuarios.adicionar(m[1], "author-redirect")
+			}
+		case resp.StatusCode == http.StatusOK:
+			if m := reClasseAutor.FindSubmatch(resp.Body); m != nil {
+				usuarios.adicionar(string(m[1]), "author-página")
+			}
+		}
+	}
+}
+
+// enumerarPorREST lê os slugs de /wp-json/wp/v2/users (ou ?rest_route=).
+func enumerarPorREST(baseURL string, usuarios usuariosEncontrados) {
+	for _, path := range []string{"wp-json/wp/v2/users?per_page=100", "?rest_route=/wp/v2/users&per_page=100"} {
+		conteudo, err := utils.GetBody(fmt.Sprintf("%s/%s", baseURL, path))
+		if err != nil {
+			continue
+		}
+		var lista []struct {
+			Slug string `json:"slug"`
+		}
+		if json.Unmarshal([]byte(conteudo), &lista) != nil || len(lista) == 0 {
+			continue
+		}
+		for _, u := range lista {
+			usuarios.adicionar(u.Slug, "rest-api")
+		}
+		return
+	}
+}
+
+// enumerarPorOEmbed lê o autor da página inicial no endpoint oEmbed (author_url traz o slug).
+func enumerarPorOEmbed(baseURL string, usuarios usuariosEncontrados) {
+	urlOEmbed := fmt.Sprintf("%s/wp-json/oembed/1.0/embed?url=%s", baseURL, url.QueryEscape(baseURL+"/"))
+	conteudo, err := utils.GetBody(urlOEmbed)
+	if err != nil {
+		return
+	}
+	var embed struct {
+		AuthorName string `json:"author_name"`
+		AuthorURL  string `json:"author_url"`
+	}
+	if json.Unmarshal([]byte(conteudo), &embed) != nil {
+		return
+	}
+	if m := reSlugAutor.FindStringSubmatch(embed.AuthorURL); m != nil {
+		usuarios.adicionar(m[1], "oembed")
+	} else if embed.AuthorName != "" {
+		usuarios.adicionar(embed.AuthorName, "oembed")
+	}
+}
+
+// enumerarPorRSS lê os autores (nome de exibição) do <dc:creator> do feed.
+func enumerarPorRSS(baseURL string, usuarios usuariosEncontrados) {
+	conteudo, err := utils.GetBody(baseURL + "/feed/")
+	if err != nil {
+		return
+	}
+	for _, m := range reCriadorRSS.FindAllStringSubmatch(conteudo, -1) {
+		usuarios.adicionar(m[1], "rss")
+	}
+}
@@ -0,0 +1,117 @@
+// internal\scanner\vcs.go
+package scanner
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"Gowpscanner/internal/utils"
+	"Gowpscanner/internal/wpdetect"
+)
+
+// listarArquivosGit ativa a reconstrução da lista de arquivos rastreados a partir do .git/index.
+var listarArquivosGit = true
+
+var (
+	reGitHead    = regexp.MustCompile(`^(ref: refs/[^\s]+|[0-9a-f]{40})\s*$`)
+	reSvnEntries = regexp.MustCompile(`^\d+\s*\n`)
+)
+
+// arquivosSensiveisVCS são nomes que, se rastreados pelo repositório, podem ser recuperados.
+var arquivosSensiveisVCS = []string{"wp-config.php", ".env", ".htpasswd", "config.php", "database.php", "settings.php", ".sql", "id_rsa", "credentials"}
+
+// sondaVCS descreve um arquivo de metadados de controle de versão e como validar seu formato.
+type sondaVCS struct {
+	sistema string
+	path    string
+	binario bool
+	valida  func(conteudo []byte) bool
+}
+
+var sondasVCS = []sondaVCS{
+	{"git", ".git/HEAD", false, func(c []byte) bool { return reGitHead.Match(bytes.TrimSpace(c)) }},
+	{"git", ".git/config", false, func(c []byte) bool { return bytes.Contains(c, []byte("[core]")) }},
+	{"git", ".git/index", true, func(c []byte) bool { return bytes.HasPrefix(c, []byte("DIRC")) }},
+	{"svn", ".svn/wc.db", true, func(c []byte) bool { return bytes.HasPrefix(c, []byte("SQLite format 3\x00")) }},
+	{"svn", ".svn/entries", false, func(c []byte) bool { return reSvnEntries.Match(c) }},
+	{"hg", ".hg/requires", false, func(c []byte) bool { return bytes.Contains(c, []byte("revlogv1")) }},
+	{"hg", ".hg/store/00manifest.i", true, validaRevlog},
+	{"bzr", ".bzr/branch-format", false, func(c []byte) bool { return bytes.Contains(c, []byte("Bazaar")) }},
+}
+
+// CheckVCS procura diretórios de controle de versão expostos (.git, .svn, .hg, .bzr), validando o formato
+// de cada arquivo. Com o .git/index acessível, lista os arquivos rastreados (apenas nomes) em vcs/<host>.txt.
+func CheckVCS(baseURL string) {
+	if !testarVCS {
+		return
+	}
+	for _, sonda := range sondasVCS {
+		urlSonda := fmt.Sprintf("%s/%s", baseURL, sonda.path)
+
+		var conteudo []byte
+		if sonda.binario {
+			resp, err := utils.GetPrefixo(urlSonda, utils.LimiteBody("vcs"))
+			if err != nil || (resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent) {
+				continue
+			}
+			conteudo = resp.Body
+		} else {
+			texto, err := utils.GetBody(urlSonda)
+			if err != nil {
+				continue
+			}
+			conteudo = []byte(texto)
+		}
+		if !sonda.valida(conteudo) {
+			continue
+		}
+
+		utils.LogSave(fmt.Sprintf("%s - %s exposto", urlSonda, sonda.sistema), "vcs.txt")
+		utils.Warning("Metadados %s expostos em %s", sonda.sistema, urlSonda)
+		utils.BeepAlert()
+
+		if sonda.path == ".git/index" && listarArquivosGit {
+			registrarArquivosGit(baseURL, conteudo)
+		}
+	}
+}
+
+// registrarArquivosGit salva os nomes dos arquivos rastreados e alerta sobre os sensíveis.
+func registrarArquivosGit(baseURL string, indice []byte) {
+	arquivos, err := wpdetect.ArquivosIndiceGit(indice)
+	if err != nil && len(arquivos) == 0 {
+		utils.Info("Não foi possível interpretar o .git/index de %s: %v", baseURL, err)
+		return
+	}
+	destino := "vcs/" + hostSemWWW(baseURL) + ".txt"
+	var sensiveis []string
+	for _, nome := range arquivos {
+		utils.LogSave(nome, destino)
+		lower := strings.ToLower(nome)
+		for _, s := range arquivosSensiveisVCS {
+			if strings.HasSuffix(lower, s) {
+				sensiveis = append(sensiveis, nome)
+				break
+			}
+		}
+	}
+	utils.Info("%d arquivos rastreados pelo git em %s (lista em retornos/%s)", len(arquivos), baseURL, destino)
+	if len(sensiveis) > 0 {
+		registro := fmt.Sprintf("%s/.git - arquivos sensíveis recuperáveis: %s", baseURL, strings.Join(sensiveis, ", "))
+		utils.LogSave(registro, "vcs.txt")
+		utils.Warning(registro)
+	}
+}
+
+// validaRevlog confere o cabeçalho de um revlog do Mercurial (versão 1 ou 2 nos bytes 2-3).
+func validaRevlog(c []byte) bool {
+	if len(c) < 4 {
+		return false
+	}
+	versao := binary.BigEndian.Uint16(c[2:4])
+	return versao == 1 || versao == 2
+}
@@ -0,0 +1,172 @@
+// internal\utils\auditoria.go
+package utils
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// registroAuditoria é uma linha do log de auditoria. Hash = SHA-256(Anterior + JSON do registro sem Hash),
+// encadeando cada requisição à anterior: qualquer linha alterada ou removida quebra a cadeia.
+// Bytes é o que o scanner leu do corpo (limitado pelo limite da checagem); Tamanho é o Content-Length
+// anunciado pelo servidor. Hora é o momento em que a requisição saiu (o registro é gravado só quando o
+// corpo é fechado, então Seq segue a ordem de conclusão). Reproduzida marca as respostas servidas pelo cassete (HTTP_MODO=reproduzir),
+// que não acessaram o alvo.
+type registroAuditoria struct {
+	Seq         int64  `json:"seq"`
+	Hora        string `json:"hora"`
+	Metodo      string `json:"metodo"`
+	URL         string `json:"url"`
+	Status      string `json:"status"`
+	Bytes       int64  `json:"bytes"`
+	Tamanho     int64  `json:"tamanho,omitempty"`
+	Reproduzida bool   `json:"reproduzida,omitempty"`
+	Anterior    string `json:"anterior"`
+	Hash        string `json:"hash,omitempty"`
+}
+
+var (
+	// auditoriaHabilitada grava todas as requisições em AUDITORIA_ARQUIVO (AUDITORIA, padrão true).
+	auditoriaHabilitada = true
+	arquivoAuditoria    = "./retornos/auditoria.jsonl"
+	muAuditoria         sync.Mutex
+	seqAuditoria        int64
+	ultimoHashAuditoria = strings.Repeat("0", 64)
+)
+
+// configurarAuditoria lê AUDITORIA e AUDITORIA_ARQUIVO e continua a cadeia do arquivo existente.
+func configurarAuditoria() error {
+	if val := os.Getenv("AUDITORIA"); val != "" {
+		auditoriaHabilitada = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("AUDITORIA_ARQUIVO"); val != "" {
+		arquivoAuditoria = val
+	}
+	if !auditoriaHabilitada {
+		return nil
+	}
+	n, ultimo, err := VerificarAuditoria(arquivoAuditoria)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("log de auditoria %s inválido: %w", arquivoAuditoria, err)
+	}
+	if n > 0 {
+		seqAuditoria, ultimoHashAuditoria = n, ultimo
+	}
+	return nil
+}
+
+// hashRegistro calcula o hash encadeado do registro (o campo Hash é ignorado).
+func hashRegistro(r registroAuditoria) string {
+	r.Hash = ""
+	dados, _ := json.Marshal(r)
+	soma := sha256.Sum256(append([]byte(r.Anterior), dados...))
+	return hex.EncodeToString(soma[:])
+}
+
+// registrarAuditoria acrescenta uma requisição ao log de auditoria (somente append). Seq e o
+// encadeamento são preenchidos aqui; Hora vem do momento da requisição.
+func registrarAuditoria(r registroAuditoria) {
+	if !auditoriaHabilitada {
+		return
+	}
+	muAuditoria.Lock()
+	defer muAuditoria.Unlock()
+
+	r.Seq = seqAuditoria + 1
+	r.Reproduzida = casseteReproducao != nil
+	r.Anterior = ultimoHashAuditoria
+	r.Hash = hashRegistro(r)
+	linha, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+	f, err := os.OpenFile(arquivoAuditoria, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		Error("Erro ao abrir o log de auditoria: %v", err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(linha, '\n')); err != nil {
+		Error("Erro ao gravar o log de auditoria: %v", err)
+		return
+	}
+	seqAuditoria, ultimoHashAuditoria = r.Seq, r.Hash
+}
+
+// VerificarAuditoria confere a cadeia de hashes do log de auditoria e retorna a quantidade de
+// registros e o hash do último. Retorna erro na primeira linha alterada, removida ou fora de ordem.
+func VerificarAuditoria(caminho string) (int64, string, error) {
+	f, err := os.Open(caminho)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+
+	anterior := strings.Repeat("0", 64)
+	var n int64
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r registroAuditoria
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return n, anterior, fmt.Errorf("linha %d: %w", n+1, err)
+		}
+		if r.Seq != n+1 || r.Anterior != anterior || r.Hash != hashRegistro(r) {
+			return n, anterior, fmt.Errorf("cadeia quebrada na linha %d (seq %d)", n+1, r.Seq)
+		}
+		n, anterior = r.Seq, r.Hash
+	}
+	return n, anterior, scanner.Err()
+}
+
+// corpoAuditado conta os bytes lidos do corpo e registra a requisição no fechamento.
+type corpoAuditado struct {
+	io.ReadCloser
+	registro registroAuditoria
+	once     sync.Once
+}
+
+func (c *corpoAuditado) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.registro.Bytes += int64(n)
+	return n, err
+}
+
+func (c *corpoAuditado) Close() error {
+	err := c.ReadCloser.Close()
+	c.once.Do(func() { registrarAuditoria(c.registro) })
+	return err
+}
+
+// horaAuditoria formata o momento da requisição para o log de auditoria.
+func horaAuditoria(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// auditarResposta registra a requisição feita em inicio: erros imediatamente, respostas quando o corpo é fechado.
+func auditarResposta(req *http.Request, resp *http.Response, err error, inicio time.Time) {
+	if err != nil {
+		registrarAuditoria(registroAuditoria{Hora: horaAuditoria(inicio), Metodo: req.Method, URL: req.URL.String(), Status: "erro: " + err.Error()})
+		return
+	}
+	registro := registroAuditoria{
+		Hora:   horaAuditoria(inicio),
+		Metodo: req.Method,
+		URL:    req.URL.String(),
+		Status: fmt.Sprintf("%d", resp.StatusCode),
+	}
+	// Content-Length desconhecido (-1) fica fora do registro
+	if resp.ContentLength > 0 {
+		registro.Tamanho = resp.ContentLength
+	}
+	resp.Body = &corpoAuditado{ReadCloser: resp.Body, registro: registro}
+}
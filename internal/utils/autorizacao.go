@@ -0,0 +1,187 @@
+// internal\utils\autorizacao.go
+package utils
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	_ "embed"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Autorizacao é o arquivo de autorização do scan: a lista de domínios que podem ser testados,
+// o identificador do engajamento e a validade, assinados com Ed25519 por quem autorizou o teste.
+//
+//	engajamento: ENG-2026-014
+//	expira: 2026-12-31T23:59:59Z
+//	dominios:
+//	  - exemplo.com.br
+//	  - "*.exemplo.com.br"
+//	assinatura: <base64 da assinatura Ed25519 de MensagemAutorizacao>
+type Autorizacao struct {
+	Engajamento string   `yaml:"engajamento"`
+	Expira      string   `yaml:"expira"`
+	Dominios    []string `yaml:"dominios"`
+	Assinatura  string   `yaml:"assinatura"`
+}
+
+var (
+	// autorizacaoAtiva restringe o scan (e todas as requisições) aos domínios autorizados.
+	autorizacaoAtiva    bool
+	dominiosAutorizados regrasEscopo
+
+	// chavesAprovadoresEmbutidas são as chaves públicas dos aprovadores, fixadas no binário
+	// (chaves_aprovadores.txt): quem opera o scan não consegue trocá-las pelo .env.
+	//go:embed chaves_aprovadores.txt
+	chavesAprovadoresEmbutidas string
+)
+
+// chaveAprovador é uma chave pública de aprovador e o nome informado no arquivo.
+type chaveAprovador struct {
+	chave ed25519.PublicKey
+	nome  string
+}
+
+// lerChavesAprovadores interpreta a lista de chaves públicas (uma por linha, seguida opcionalmente
+// do nome do aprovador; linhas vazias e comentários com # são ignorados).
+func lerChavesAprovadores(conteudo string) ([]chaveAprovador, error) {
+	var chaves []chaveAprovador
+	scanner := bufio.NewScanner(strings.NewReader(conteudo))
+	for n := 1; scanner.Scan(); n++ {
+		campos := strings.Fields(scanner.Text())
+		if len(campos) == 0 || strings.HasPrefix(campos[0], "#") {
+			continue
+		}
+		chave, err := DecodificarChave(campos[0])
+		if err != nil || len(chave) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("linha %d: a chave do aprovador deve ser uma chave pública Ed25519 de 32 bytes (hex ou base64)", n)
+		}
+		nome := strings.Join(campos[1:], " ")
+		if nome == "" {
+			nome = hex.EncodeToString(chave)[:16]
+		}
+		chaves = append(chaves, chaveAprovador{chave: chave, nome: nome})
+	}
+	return chaves, scanner.Err()
+}
+
+// MensagemAutorizacao monta o texto assinado: versão do formato, engajamento, validade e domínios, um por linha.
+func MensagemAutorizacao(a *Autorizacao) []byte {
+	var sb strings.Builder
+	sb.WriteString("gowpscanner-autorizacao-v1\n")
+	sb.WriteString("engajamento:" + strings.TrimSpace(a.Engajamento) + "\n")
+	sb.WriteString("expira:" + strings.TrimSpace(a.Expira) + "\n")
+	for _, d := range a.Dominios {
+		sb.WriteString("dominio:" + strings.ToLower(strings.TrimSpace(d)) + "\n")
+	}
+	return []byte(sb.String())
+}
+
+// DecodificarChave aceita uma chave em hex ou base64.
+func DecodificarChave(chave string) ([]byte, error) {
+	chave = strings.TrimSpace(chave)
+	if dados, err := hex.DecodeString(chave); err == nil {
+		return dados, nil
+	}
+	return base64.StdEncoding.DecodeString(chave)
+}
+
+// validadeAutorizacao interpreta a data de expiração (RFC 3339 ou AAAA-MM-DD, válida até o fim do dia).
+func validadeAutorizacao(expira string) (time.Time, error) {
+	expira = strings.TrimSpace(expira)
+	if t, err := time.Parse(time.RFC3339, expira); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", expira)
+	if err != nil {
+		return t, fmt.Errorf("data de expiração inválida %q", expira)
+	}
+	return t.Add(24*time.Hour - time.Nanosecond), nil
+}
+
+// CarregarAutorizacao lê o arquivo de autorização, confere a assinatura com as chaves dos aprovadores
+// embutidas no binário e a validade, e restringe o scan aos domínios autorizados. Retorna também o
+// nome do aprovador cuja chave validou a assinatura.
+func CarregarAutorizacao(caminho string) (*Autorizacao, string, error) {
+	aprovadores, err := lerChavesAprovadores(chavesAprovadoresEmbutidas)
+	if err != nil {
+		return nil, "", fmt.Errorf("chaves_aprovadores.txt: %w", err)
+	}
+	if len(aprovadores) == 0 {
+		return nil, "", errors.New("nenhuma chave de aprovador embutida no binário (internal/utils/chaves_aprovadores.txt)")
+	}
+	a, aprovador, err := lerAutorizacao(caminho, aprovadores)
+	if err != nil {
+		return nil, "", err
+	}
+	if dominiosAutorizados, err = compilarEscopo(a.Dominios); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", caminho, err)
+	}
+	autorizacaoAtiva = true
+	return a, aprovador, nil
+}
+
+// lerAutorizacao lê o arquivo e confere a assinatura (com qualquer uma das chaves dos aprovadores) e a validade.
+func lerAutorizacao(caminho string, aprovadores []chaveAprovador) (*Autorizacao, string, error) {
+	data, err := os.ReadFile(caminho)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao ler o arquivo de autorização %s: %w", caminho, err)
+	}
+	var a Autorizacao
+	if err := yaml.Unmarshal(data, &a); err != nil {
+		return nil, "", fmt.Errorf("erro ao interpretar %s: %w", caminho, err)
+	}
+	if a.Engajamento == "" || len(a.Dominios) == 0 {
+		return nil, "", fmt.Errorf("%s: engajamento e dominios são obrigatórios", caminho)
+	}
+
+	assinatura, err := base64.StdEncoding.DecodeString(strings.TrimSpace(a.Assinatura))
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: assinatura inválida", caminho)
+	}
+	aprovador := ""
+	for _, c := range aprovadores {
+		if ed25519.Verify(c.chave, MensagemAutorizacao(&a), assinatura) {
+			aprovador = c.nome
+			break
+		}
+	}
+	if aprovador == "" {
+		return nil, "", fmt.Errorf("%s: assinatura inválida (nenhum aprovador conhecido)", caminho)
+	}
+	validade, err := validadeAutorizacao(a.Expira)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", caminho, err)
+	}
+	if time.Now().After(validade) {
+		return nil, "", fmt.Errorf("%s: autorização do engajamento %s expirou em %s", caminho, a.Engajamento, a.Expira)
+	}
+	return &a, aprovador, nil
+}
+
+// AssinarAutorizacao preenche a assinatura do arquivo de autorização com a chave privada Ed25519.
+func AssinarAutorizacao(a *Autorizacao, chavePrivada ed25519.PrivateKey) {
+	a.Assinatura = base64.StdEncoding.EncodeToString(ed25519.Sign(chavePrivada, MensagemAutorizacao(a)))
+}
+
+// DominioAutorizado retorna nil se o host estiver na autorização carregada (ou se não houver autorização).
+func DominioAutorizado(host string) error {
+	if !autorizacaoAtiva {
+		return nil
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if dominiosAutorizados.contemHost(host) {
+		return nil
+	}
+	if len(dominiosAutorizados.redes) > 0 && dominiosAutorizados.contemTodosIPs(ipsDoHost(host)) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s não consta na autorização", ErrForaDoEscopo, host)
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+// gravarAutorizacao assina a autorização com a chave privada e grava o YAML num diretório temporário.
+func gravarAutorizacao(t *testing.T, a Autorizacao, privada ed25519.PrivateKey) string {
+	t.Helper()
+	AssinarAutorizacao(&a, privada)
+	dados, err := yaml.Marshal(&a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caminho := filepath.Join(t.TempDir(), "autorizacao.yml")
+	if err := os.WriteFile(caminho, dados, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return caminho
+}
+
+func TestLerChavesAprovadores(t *testing.T) {
+	publica, _, _ := ed25519.GenerateKey(rand.Reader)
+	conteudo := "# comentário\n\n" + hex.EncodeToString(publica) + " Equipe de Segurança\n"
+	chaves, err := lerChavesAprovadores(conteudo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chaves) != 1 || chaves[0].nome != "Equipe de Segurança" || !chaves[0].chave.Equal(publica) {
+		t.Errorf("chaves lidas: %+v", chaves)
+	}
+	if _, err := lerChavesAprovadores("abcd\n"); err == nil {
+		t.Error("chave de tamanho inválido aceita")
+	}
+	// O arquivo distribuído só tem comentários
+	if chaves, err := lerChavesAprovadores(chavesAprovadoresEmbutidas); err != nil || len(chaves) != 0 {
+		t.Errorf("chaves_aprovadores.txt: %d chaves, erro %v", len(chaves), err)
+	}
+}
+
+func TestLerAutorizacao(t *testing.T) {
+	publica, privada, _ := ed25519.GenerateKey(rand.Reader)
+	_, outraPrivada, _ := ed25519.GenerateKey(rand.Reader)
+	aprovadores := []chaveAprovador{{chave: publica, nome: "aprovador"}}
+	valida := Autorizacao{Engajamento: "ENG-1", Expira: "2999-12-31", Dominios: []string{"exemplo.com.br"}}
+
+	a, aprovador, err := lerAutorizacao(gravarAutorizacao(t, valida, privada), aprovadores)
+	if err != nil || aprovador != "aprovador" || a.Engajamento != "ENG-1" {
+		t.Fatalf("autorização válida rejeitada: %v", err)
+	}
+
+	casos := map[string]string{
+		"assinada por quem não é aprovador": gravarAutorizacao(t, valida, outraPrivada),
+		"expirada":                          gravarAutorizacao(t, Autorizacao{Engajamento: "ENG-1", Expira: "2020-01-01", Dominios: []string{"exemplo.com.br"}}, privada),
+	}
+	for nome, caminho := range casos {
+		if _, _, err := lerAutorizacao(caminho, aprovadores); err == nil {
+			t.Errorf("%s: autorização aceita", nome)
+		}
+	}
+
+	// Domínio acrescentado depois da assinatura
+	caminho := gravarAutorizacao(t, valida, privada)
+	dados, _ := os.ReadFile(caminho)
+	alterado := strings.Replace(string(dados), "- exemplo.com.br", "- exemplo.com.br\n- atacante.com", 1)
+	if alterado == string(dados) {
+		t.Fatal("lista de domínios não encontrada no YAML gerado")
+	}
+	os.WriteFile(caminho, []byte(alterado), 0600)
+	if _, _, err := lerAutorizacao(caminho, aprovadores); err == nil {
+		t.Error("autorização alterada aceita")
+	}
+}
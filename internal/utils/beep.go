@@ -1,13 +1,13 @@
 // internal\utils\beep.go
+
+//go:build windows
+
 package utils
 
 import (
 	"syscall"
 )
 
-// Se quiser fazer condicional para Windows:
-// // +build windows
-
 var (
 	user32, _      = syscall.LoadLibrary("user32.dll")
 	messageBeep, _ = syscall.GetProcAddress(user32, "MessageBeep")
@@ -0,0 +1,8 @@
+// internal\utils\beep_outros.go
+
+//go:build !windows
+
+package utils
+
+// BeepAlert não faz nada fora do Windows (o beep usa a user32.dll).
+func BeepAlert() {}
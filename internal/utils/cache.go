@@ -0,0 +1,110 @@
+// internal\utils\cache.go
+package utils
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+var (
+	// cacheHabilitado controla o cache de respostas (CACHE_HTTP=false desativa).
+	cacheHabilitado = true
+	// tamanhoMaximoCache é o maior corpo mantido no cache (CACHE_TAMANHO_MAXIMO, em bytes). Corpos maiores
+	// (dumps, backups, logs) são entregues a quem os pediu e descartados, para não acumular memória.
+	tamanhoMaximoCache = 1024 * 1024
+)
+
+// chamadaCache guarda o resultado de uma requisição. Enquanto a primeira goroutine
+// busca a URL, as demais aguardam no WaitGroup e recebem o mesmo resultado (single-flight).
+type chamadaCache struct {
+	wg   sync.WaitGroup
+	resp *Resposta
+	err  error
+}
+
+// cacheAlvo é o cache de respostas de um único host.
+type cacheAlvo struct {
+	mu       sync.Mutex
+	entradas map[string]*chamadaCache
+}
+
+// caches associa cada host ao seu cache de respostas.
+var caches sync.Map // key: string (host sem porta), value: *cacheAlvo
+
+// hostDaURL extrai o host (sem porta, em minúsculas) de uma URL.
+func hostDaURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// buscarComCache busca a URL uma única vez por scan: requisições repetidas (ou simultâneas)
+// para o mesmo método e URL compartilham a mesma resposta.
+func buscarComCache(p pedido) (*Resposta, error) {
+	if !cacheHabilitado {
+		return requisitar(p)
+	}
+
+	iface, _ := caches.LoadOrStore(hostDaURL(p.url), &cacheAlvo{entradas: make(map[string]*chamadaCache)})
+	alvo := iface.(*cacheAlvo)
+	chave := p.chave()
+
+	alvo.mu.Lock()
+	if chamada, ok := alvo.entradas[chave]; ok {
+		alvo.mu.Unlock()
+		chamada.wg.Wait()
+		// Uma resposta truncada num limite menor não serve para quem pediu mais bytes
+		if chamada.err == nil && chamada.resp.Truncado && chamada.resp.limite < p.limite &&
+			(p.prefixo || !TipoBinario(chamada.resp.Tipo)) {
+			return requisitar(p)
+		}
+		if chamada.err == nil {
+			return limitarResposta(chamada.resp, p.limite), nil
+		}
+		return chamada.resp, chamada.err
+	}
+	chamada := &chamadaCache{}
+	chamada.wg.Add(1)
+	alvo.entradas[chave] = chamada
+	alvo.mu.Unlock()
+
+	chamada.resp, chamada.err = requisitar(p)
+	chamada.wg.Done()
+	// Quem já está aguardando recebe a resposta; as próximas chamadas buscam de novo. Erros (timeout,
+	// conexão reiniciada) também não ficam no cache: podem ser transitórios.
+	if chamada.err != nil || len(chamada.resp.Body) > tamanhoMaximoCache {
+		alvo.mu.Lock()
+		delete(alvo.entradas, chave)
+		alvo.mu.Unlock()
+	}
+	return chamada.resp, chamada.err
+}
+
+// limitarResposta devolve a resposta em cache cortada no limite de quem pediu: uma checagem com limite
+// menor não recebe mais bytes do que pediria à rede.
+func limitarResposta(resp *Resposta, limite int64) *Resposta {
+	if limite <= 0 || int64(len(resp.Body)) <= limite {
+		return resp
+	}
+	cortada := *resp
+	cortada.Body = resp.Body[:limite:limite]
+	cortada.Truncado = true
+	cortada.limite = limite
+	return &cortada
+}
+
+// LimparCache descarta as respostas guardadas para os hosts informados (sem a porta). Só os hosts exatos
+// são limpos: subdomínios podem ser alvos de outros scans em andamento. Deve ser chamada ao final do scan
+// de cada alvo com todos os hosts que ele acessou.
+func LimparCache(hosts ...string) {
+	for _, h := range hosts {
+		if semPorta, _, err := net.SplitHostPort(h); err == nil {
+			h = semPorta
+		}
+		caches.Delete(strings.ToLower(strings.TrimSuffix(h, ".")))
+	}
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// usarCache liga o cache (sem auditoria) e o esvazia ao final do teste.
+func usarCache(t *testing.T) {
+	t.Helper()
+	cache, auditoria := cacheHabilitado, auditoriaHabilitada
+	cacheHabilitado, auditoriaHabilitada = true, false
+	t.Cleanup(func() {
+		cacheHabilitado, auditoriaHabilitada = cache, auditoria
+		caches.Range(func(chave, _ interface{}) bool { caches.Delete(chave); return true })
+	})
+}
+
+func TestCacheNaoGuardaErros(t *testing.T) {
+	usarCache(t)
+	var chamadas atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A primeira requisição cai com a conexão reiniciada
+		if chamadas.Add(1) == 1 {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	if _, err := GetBody(srv.URL + "/x"); err == nil {
+		t.Fatal("a primeira requisição deveria falhar")
+	}
+	corpo, err := GetBody(srv.URL + "/x")
+	if err != nil || corpo != "ok" {
+		t.Fatalf("erro transitório servido pelo cache: %q, %v", corpo, err)
+	}
+	GetBody(srv.URL + "/x")
+	if n := chamadas.Load(); n != 2 {
+		t.Errorf("%d requisições, esperado 2 (a resposta boa fica no cache)", n)
+	}
+}
+
+func TestCacheRespeitaLimiteDeQuemPede(t *testing.T) {
+	usarCache(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", 1000)))
+	}))
+	defer srv.Close()
+
+	if corpo, err := GetBodyLimitado(srv.URL, 4096); err != nil || len(corpo) != 1000 {
+		t.Fatalf("corpo completo: %d bytes, %v", len(corpo), err)
+	}
+	if _, err := GetBodyLimitado(srv.URL, 100); err == nil {
+		t.Error("corpo em cache maior que o limite entregue sem corte")
+	}
+	resp, err := GetPrefixo(srv.URL, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Body) > 100 {
+		t.Errorf("prefixo com %d bytes, limite 100", len(resp.Body))
+	}
+	// A resposta guardada continua completa para quem pede mais
+	if corpo, err := GetBodyLimitado(srv.URL, 4096); err != nil || len(corpo) != 1000 {
+		t.Errorf("corpo completo após o corte: %d bytes, %v", len(corpo), err)
+	}
+}
+
+func TestLimparCacheSoHostsExatos(t *testing.T) {
+	usarCache(t)
+	for _, host := range []string{"exemplo.com", "www.exemplo.com", "loja.exemplo.com"} {
+		caches.Store(host, &cacheAlvo{entradas: make(map[string]*chamadaCache)})
+	}
+	LimparCache("Exemplo.com:8443", "www.exemplo.com")
+	for host, esperado := range map[string]bool{"exemplo.com": false, "www.exemplo.com": false, "loja.exemplo.com": true} {
+		if _, existe := caches.Load(host); existe != esperado {
+			t.Errorf("cache de %s: existe = %v, esperado %v", host, existe, esperado)
+		}
+	}
+}
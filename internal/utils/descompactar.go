@@ -0,0 +1,119 @@
+// internal\utils\descompactar.go
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ArquivoDescompactado é um arquivo extraído de um conteúdo gzip ou zip.
+type ArquivoDescompactado struct {
+	Nome     string
+	Conteudo []byte
+}
+
+// Descompactar extrai o conteúdo gzip (um arquivo) ou zip (todos os arquivos), lendo no máximo
+// limite bytes descompactados no total (proteção contra bombas de compressão). Arquivos que
+// passariam do limite são descartados.
+func Descompactar(dados []byte, limite int64) ([]ArquivoDescompactado, error) {
+	switch DetectarTipo(dados[:min(len(dados), tamanhoSniff)]) {
+	case "gzip":
+		r, err := gzip.NewReader(bytes.NewReader(dados))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		conteudo, err := lerAteLimite(r, limite)
+		if err != nil {
+			return nil, err
+		}
+		nome := r.Name
+		if nome == "" {
+			nome = "gzip"
+		}
+		return []ArquivoDescompactado{{Nome: nome, Conteudo: conteudo}}, nil
+
+	case "zip":
+		r, err := zip.NewReader(bytes.NewReader(dados), int64(len(dados)))
+		if err != nil {
+			return nil, err
+		}
+		var arquivos []ArquivoDescompactado
+		restante := limite
+		for _, f := range r.File {
+			if f.FileInfo().IsDir() || int64(f.UncompressedSize64) > restante {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				continue
+			}
+			conteudo, err := lerAteLimite(rc, restante)
+			rc.Close()
+			if err != nil {
+				continue
+			}
+			restante -= int64(len(conteudo))
+			arquivos = append(arquivos, ArquivoDescompactado{Nome: f.Name, Conteudo: conteudo})
+		}
+		return arquivos, nil
+	}
+	return nil, fmt.Errorf("conteúdo não compactado")
+}
+
+// lerAteLimite lê tudo do reader, retornando erro se passar do limite.
+func lerAteLimite(r io.Reader, limite int64) ([]byte, error) {
+	dados, err := io.ReadAll(io.LimitReader(r, limite+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(dados)) > limite {
+		return nil, fmt.Errorf("conteúdo descompactado excede o limite de %d bytes", limite)
+	}
+	return dados, nil
+}
+
+// GetBodyDescompactado funciona como GetBodyLimitado, mas aceita arquivos gzip ou zip servidos no
+// caminho: o arquivo compactado (até o limite) é descompactado e os arquivos de texto são concatenados.
+func GetBodyDescompactado(url string, limite int64) (string, error) {
+	conteudo, err := GetBodyLimitado(url, limite)
+	if err == nil {
+		return conteudo, nil
+	}
+	// A resposta já está em cache; só busca o arquivo inteiro se for gzip/zip
+	resp, errBusca := buscarComCache(pedido{metodo: "GET", url: url, limite: limite})
+	if errBusca != nil || resp.StatusCode != http.StatusOK || (resp.Tipo != "gzip" && resp.Tipo != "zip") {
+		return "", err
+	}
+	completo, err := GetPrefixo(url, limite)
+	if err != nil {
+		return "", err
+	}
+	if completo.StatusCode != http.StatusOK && completo.StatusCode != http.StatusPartialContent {
+		return "", fmt.Errorf("status code %d", completo.StatusCode)
+	}
+	if completo.Truncado || (completo.Tamanho > 0 && int64(len(completo.Body)) < completo.Tamanho) {
+		return "", fmt.Errorf("arquivo %s excede o limite de %d bytes", resp.Tipo, limite)
+	}
+	arquivos, err := Descompactar(completo.Body, limite)
+	if err != nil {
+		return "", err
+	}
+	var sb bytes.Buffer
+	for _, a := range arquivos {
+		inicio := a.Conteudo[:min(len(a.Conteudo), tamanhoSniff)]
+		if TipoBinario(DetectarTipo(inicio)) {
+			continue
+		}
+		sb.Write(a.Conteudo)
+		sb.WriteByte('\n')
+	}
+	if sb.Len() == 0 {
+		return "", fmt.Errorf("nenhum arquivo de texto em %s", resp.Tipo)
+	}
+	return sb.String(), nil
+}
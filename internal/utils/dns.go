@@ -0,0 +1,273 @@
+// internal\utils\dns.go
+package utils
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResolucaoDNS guarda o resultado da resolução de um host.
+type ResolucaoDNS struct {
+	Host   string
+	IPs    []string
+	CNAMEs []string // cadeia de CNAMEs, na ordem em que foram seguidos
+	Fonte  string   // hosts, doh, servidor ou sistema
+	// SoNomeFinal indica que CNAMEs tem só o nome canônico final: o resolvedor do Go não expõe os
+	// CNAMEs intermediários (a cadeia completa só vem com DNS_DOH)
+	SoNomeFinal bool
+}
+
+var (
+	// dnsServidor é o servidor DNS (host:porta) usado no lugar do resolvedor do sistema (DNS_SERVIDOR).
+	dnsServidor string
+	// dnsDoH é o endpoint DNS-over-HTTPS com API JSON (DNS_DOH).
+	dnsDoH string
+	// dnsOverrides fixa hosts em IPs específicos, no formato do /etc/hosts (DNS_HOSTS).
+	dnsOverrides = make(map[string][]string)
+	// PreResolverDNS faz a resolução antes do scan e pula alvos sem resposta (DNS_PRE_RESOLVER).
+	PreResolverDNS = true
+
+	resolvedor   = net.DefaultResolver
+	resolucoes   sync.Map // key: host, value: *ResolucaoDNS
+	clienteDoH   = &http.Client{Timeout: 5 * time.Second}
+	discadorBase = &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
+)
+
+// ErrSemRespostaDNS indica que o host não possui registros A/AAAA.
+var ErrSemRespostaDNS = errors.New("sem resposta DNS")
+
+// configurarDNS lê DNS_SERVIDOR, DNS_DOH, DNS_HOSTS e DNS_PRE_RESOLVER.
+func configurarDNS() error {
+	if val := os.Getenv("DNS_PRE_RESOLVER"); val != "" {
+		PreResolverDNS = strings.ToLower(val) == "true"
+	}
+	dnsDoH = os.Getenv("DNS_DOH")
+	if val := os.Getenv("DNS_SERVIDOR"); val != "" {
+		if _, _, err := net.SplitHostPort(val); err != nil {
+			val = net.JoinHostPort(val, "53")
+		}
+		dnsServidor = val
+		resolvedor = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return discadorBase.DialContext(ctx, network, dnsServidor)
+			},
+		}
+	}
+	if caminho := os.Getenv("DNS_HOSTS"); caminho != "" {
+		if err := carregarHostsDNS(caminho); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// carregarHostsDNS lê um arquivo no formato do /etc/hosts ("IP host [host...]"), usado para
+// fixar um host num IP específico (ex.: escanear o servidor de origem atrás de uma CDN).
+func carregarHostsDNS(caminho string) error {
+	f, err := os.Open(caminho)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir DNS_HOSTS %s: %w", caminho, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		linha := scanner.Text()
+		if idx := strings.Index(linha, "#"); idx != -1 {
+			linha = linha[:idx]
+		}
+		campos := strings.Fields(linha)
+		if len(campos) < 2 || net.ParseIP(campos[0]) == nil {
+			continue
+		}
+		for _, host := range campos[1:] {
+			host = strings.ToLower(host)
+			dnsOverrides[host] = append(dnsOverrides[host], campos[0])
+		}
+	}
+	return scanner.Err()
+}
+
+// ResolverHost resolve A/AAAA/CNAME do host usando (nesta ordem) DNS_HOSTS, DNS_DOH,
+// DNS_SERVIDOR ou o resolvedor do sistema. O resultado fica em cache durante o scan.
+func ResolverHost(host string) (*ResolucaoDNS, error) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if net.ParseIP(host) != nil {
+		return &ResolucaoDNS{Host: host, IPs: []string{host}, Fonte: "ip"}, nil
+	}
+	if iface, ok := resolucoes.Load(host); ok {
+		r := iface.(*ResolucaoDNS)
+		if len(r.IPs) == 0 {
+			return r, ErrSemRespostaDNS
+		}
+		return r, nil
+	}
+
+	var r *ResolucaoDNS
+	var err error
+	if ips, ok := dnsOverrides[host]; ok {
+		r = &ResolucaoDNS{Host: host, IPs: ips, Fonte: "hosts"}
+	} else if dnsDoH != "" {
+		r, err = resolverDoH(host)
+	} else {
+		r, err = resolverNativo(host)
+	}
+	if err != nil {
+		// Erros de rede não são guardados em cache, apenas respostas sem registros.
+		var dnsErr *net.DNSError
+		if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+			return nil, err
+		}
+		r = &ResolucaoDNS{Host: host, Fonte: "servidor"}
+	}
+	resolucoes.Store(host, r)
+	if len(r.IPs) == 0 {
+		return r, ErrSemRespostaDNS
+	}
+	return r, nil
+}
+
+// resolverNativo usa o resolvedor do Go (sistema ou DNS_SERVIDOR).
+func resolverNativo(host string) (*ResolucaoDNS, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	r := &ResolucaoDNS{Host: host, Fonte: "sistema"}
+	if dnsServidor != "" {
+		r.Fonte = "servidor"
+	}
+	// LookupCNAME segue a cadeia inteira e retorna apenas o nome canônico final
+	if cname, err := resolvedor.LookupCNAME(ctx, host); err == nil {
+		cname = strings.TrimSuffix(cname, ".")
+		if cname != "" && cname != host {
+			r.CNAMEs = append(r.CNAMEs, cname)
+			r.SoNomeFinal = true
+		}
+	}
+	addrs, err := resolvedor.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range addrs {
+		r.IPs = append(r.IPs, a.IP.String())
+	}
+	return r, nil
+}
+
+// respostaDoH é o formato JSON (application/dns-json) dos provedores DoH.
+type respostaDoH struct {
+	Status int `json:"Status"`
+	Answer []struct {
+		Name string `json:"name"`
+		Type int    `json:"type"`
+		Data string `json:"data"`
+	} `json:"Answer"`
+}
+
+// resolverDoH consulta A e AAAA via DNS-over-HTTPS; os CNAMEs vêm na própria resposta.
+func resolverDoH(host string) (*ResolucaoDNS, error) {
+	r := &ResolucaoDNS{Host: host, Fonte: "doh"}
+	vistos := make(map[string]bool)
+	for _, tipo := range []string{"A", "AAAA"} {
+		q := url.Values{"name": {host}, "type": {tipo}}
+		req, err := http.NewRequest("GET", dnsDoH+"?"+q.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/dns-json")
+		resp, err := clienteDoH.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("DoH %s respondeu com status %d", dnsDoH, resp.StatusCode)
+		}
+		var dados respostaDoH
+		err = json.NewDecoder(io.LimitReader(resp.Body, 1024*1024)).Decode(&dados)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("resposta DoH inválida: %w", err)
+		}
+		// Só NOERROR (0) e NXDOMAIN (3) são respostas; SERVFAIL, REFUSED etc. não podem virar "sem registros"
+		if dados.Status != 0 && dados.Status != 3 {
+			return nil, fmt.Errorf("DoH %s respondeu com rcode %d para %s", dnsDoH, dados.Status, host)
+		}
+		for _, a := range dados.Answer {
+			valor := strings.TrimSuffix(a.Data, ".")
+			switch a.Type {
+			case 1, 28: // A, AAAA
+				r.IPs = append(r.IPs, valor)
+			case 5: // CNAME
+				if !vistos[valor] {
+					vistos[valor] = true
+					r.CNAMEs = append(r.CNAMEs, valor)
+				}
+			}
+		}
+	}
+	if len(r.IPs) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return r, nil
+}
+
+// usaResolucaoPropria indica se as conexões devem usar ResolverHost em vez do resolvedor padrão.
+func usaResolucaoPropria() bool {
+	return dnsServidor != "" || dnsDoH != "" || len(dnsOverrides) > 0
+}
+
+// discar abre a conexão TCP usando a resolução configurada (overrides, DoH ou servidor próprio).
+// Com redes (CIDR/IP) no escopo, o host também é resolvido aqui e cada IP é conferido antes de ser
+// discado: a decisão vale para o endereço efetivamente conectado, não para uma resolução anterior.
+func discar(ctx context.Context, network, addr string) (net.Conn, error) {
+	if !usaResolucaoPropria() && !escopoComRedes() {
+		return discadorBase.DialContext(ctx, network, addr)
+	}
+	host, porta, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	r, err := ResolverHost(host)
+	if err != nil {
+		return nil, err
+	}
+	var ultimoErro error
+	for _, ip := range r.IPs {
+		if err := verificarIPDiscado(host, net.ParseIP(ip)); err != nil {
+			LogSave(fmt.Sprintf("%s - conexão a %s bloqueada - %v", time.Now().Format(time.RFC3339), net.JoinHostPort(ip, porta), err), "escopo-bloqueados.txt")
+			ultimoErro = err
+			continue
+		}
+		conn, err := discadorBase.DialContext(ctx, network, net.JoinHostPort(ip, porta))
+		if err == nil {
+			return conn, nil
+		}
+		ultimoErro = err
+	}
+	return nil, ultimoErro
+}
+
+// RegistrarDNS salva os IPs e a cadeia de CNAMEs do host em dns.txt.
+func RegistrarDNS(r *ResolucaoDNS) {
+	cadeia := "-"
+	if len(r.CNAMEs) > 0 {
+		cadeia = strings.Join(r.CNAMEs, " -> ")
+	}
+	if r.SoNomeFinal {
+		cadeia += " (só o nome final; cadeia completa com DNS_DOH)"
+	}
+	LogSave(fmt.Sprintf("%s | IPs: %s | CNAME: %s | fonte: %s", r.Host, strings.Join(r.IPs, ","), cadeia, r.Fonte), "dns.txt")
+}
@@ -0,0 +1,278 @@
+// internal\utils\escopo.go
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ErrForaDoEscopo é retornado para toda requisição a um host fora do escopo definido.
+var ErrForaDoEscopo = errors.New("fora do escopo")
+
+// regrasEscopo é um conjunto de domínios exatos, curingas (*.dominio) e redes (CIDR ou IP).
+type regrasEscopo struct {
+	dominios map[string]bool
+	curingas []string // sufixos ".dominio" dos curingas
+	redes    []*net.IPNet
+}
+
+// arquivoEscopo é o formato do arquivo indicado em ESCOPO_ARQUIVO.
+type arquivoEscopo struct {
+	Permitidos []string `yaml:"permitidos"`
+	Excluidos  []string `yaml:"excluidos"`
+}
+
+var (
+	// escopoAtivo indica se o escopo foi definido; sem ESCOPO_ARQUIVO nenhuma requisição é bloqueada.
+	escopoAtivo      bool
+	escopoPermitidos regrasEscopo
+	escopoExcluidos  regrasEscopo
+
+	// hostsBloqueados evita repetir o aviso no terminal para o mesmo host.
+	hostsBloqueados sync.Map
+)
+
+// configurarEscopo lê o arquivo ESCOPO_ARQUIVO (YAML). Exemplo:
+//
+//	permitidos:
+//	  - exemplo.com.br          # apenas o host exato
+//	  - "*.exemplo.com.br"      # qualquer subdomínio
+//	  - 203.0.113.0/24          # hosts que resolvem para esta rede
+//	excluidos:
+//	  - admin.exemplo.com.br
+//	  - 203.0.113.7
+//
+// As exclusões têm prioridade sobre as permissões.
+func configurarEscopo() error {
+	caminho := os.Getenv("ESCOPO_ARQUIVO")
+	if caminho == "" {
+		return nil
+	}
+	data, err := os.ReadFile(caminho)
+	if err != nil {
+		return fmt.Errorf("erro ao ler ESCOPO_ARQUIVO %s: %w", caminho, err)
+	}
+	var arq arquivoEscopo
+	if err := yaml.Unmarshal(data, &arq); err != nil {
+		return fmt.Errorf("erro ao interpretar %s: %w", caminho, err)
+	}
+	if err := DefinirEscopo(arq.Permitidos, arq.Excluidos); err != nil {
+		return fmt.Errorf("%s: %w", caminho, err)
+	}
+	Info("Escopo carregado de %s (%d permitidos, %d excluídos)", caminho, len(arq.Permitidos), len(arq.Excluidos))
+	return nil
+}
+
+// DefinirEscopo ativa o escopo com as entradas permitidas e excluídas (domínios, *.curingas, CIDRs ou IPs).
+func DefinirEscopo(permitidos, excluidos []string) error {
+	if len(permitidos) == 0 {
+		return errors.New("escopo sem nenhuma entrada permitida")
+	}
+	var err error
+	if escopoPermitidos, err = compilarEscopo(permitidos); err != nil {
+		return err
+	}
+	if escopoExcluidos, err = compilarEscopo(excluidos); err != nil {
+		return err
+	}
+	escopoAtivo = true
+	return nil
+}
+
+// EscopoAtivo indica se as requisições estão restritas a um escopo.
+func EscopoAtivo() bool {
+	return escopoAtivo
+}
+
+// compilarEscopo separa as entradas em domínios, curingas e redes.
+func compilarEscopo(entradas []string) (regrasEscopo, error) {
+	r := regrasEscopo{dominios: make(map[string]bool)}
+	for _, e := range entradas {
+		e = strings.ToLower(strings.TrimSpace(e))
+		switch {
+		case e == "":
+			continue
+		case strings.Contains(e, "/"):
+			_, rede, err := net.ParseCIDR(e)
+			if err != nil {
+				return r, fmt.Errorf("CIDR inválido %q: %w", e, err)
+			}
+			r.redes = append(r.redes, rede)
+		case net.ParseIP(e) != nil:
+			ip := net.ParseIP(e)
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			r.redes = append(r.redes, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		case strings.HasPrefix(e, "*."):
+			r.curingas = append(r.curingas, e[1:])
+		default:
+			r.dominios[strings.TrimSuffix(e, ".")] = true
+		}
+	}
+	return r, nil
+}
+
+// contemHost indica se o host casa com um domínio ou curinga das regras.
+func (r regrasEscopo) contemHost(host string) bool {
+	if r.dominios[host] {
+		return true
+	}
+	for _, sufixo := range r.curingas {
+		if strings.HasSuffix(host, sufixo) {
+			return true
+		}
+	}
+	return false
+}
+
+// contemIP indica se o IP pertence a uma das redes das regras.
+func (r regrasEscopo) contemIP(ip net.IP) bool {
+	for _, rede := range r.redes {
+		if rede.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// contemTodosIPs indica se há IPs e todos pertencem às redes das regras.
+func (r regrasEscopo) contemTodosIPs(ips []net.IP) bool {
+	if len(ips) == 0 {
+		return false
+	}
+	for _, ip := range ips {
+		if !r.contemIP(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// escopoComRedes indica se o escopo tem redes (CIDR/IP), que precisam ser conferidas no IP discado.
+func escopoComRedes() bool {
+	return escopoAtivo && (len(escopoExcluidos.redes) > 0 || len(escopoPermitidos.redes) > 0)
+}
+
+// verificarIPDiscado confere as redes do escopo no IP que vai ser discado para o host (chamada em discar).
+// Hosts permitidos por domínio ou curinga só são barrados pelas redes excluídas; os demais precisam
+// que o IP esteja numa rede permitida.
+func verificarIPDiscado(host string, ip net.IP) error {
+	if !escopoAtivo {
+		return nil
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if ip == nil {
+		return fmt.Errorf("%w: %s resolve para um IP inválido", ErrForaDoEscopo, host)
+	}
+	if escopoExcluidos.contemIP(ip) {
+		return fmt.Errorf("%w: %s resolve para %s (excluído)", ErrForaDoEscopo, host, ip)
+	}
+	if escopoPermitidos.contemHost(host) || escopoPermitidos.contemIP(ip) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s resolve para %s, fora das redes permitidas", ErrForaDoEscopo, host, ip)
+}
+
+// ipsDoHost retorna os IPs do host (ele mesmo, se já for um IP) usando a resolução DNS configurada.
+func ipsDoHost(host string) []net.IP {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}
+	}
+	res, err := ResolverHost(host)
+	if err != nil {
+		return nil
+	}
+	var ips []net.IP
+	for _, s := range res.IPs {
+		if ip := net.ParseIP(s); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+// VerificarEscopo retorna nil se o host estiver na autorização e no escopo, ou o motivo do bloqueio.
+// Hosts são resolvidos apenas quando há redes (CIDR/IP) nas regras. A checagem das redes aqui é só um
+// filtro prévio (entradas e requisições); a que vale é a de discar, feita no IP efetivamente conectado.
+func VerificarEscopo(host string) error {
+	if err := DominioAutorizado(host); err != nil {
+		return err
+	}
+	if !escopoAtivo {
+		return nil
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if escopoExcluidos.contemHost(host) {
+		return fmt.Errorf("%w: %s excluído", ErrForaDoEscopo, host)
+	}
+	var ips []net.IP
+	if len(escopoExcluidos.redes) > 0 || len(escopoPermitidos.redes) > 0 {
+		ips = ipsDoHost(host)
+	}
+	for _, ip := range ips {
+		if escopoExcluidos.contemIP(ip) {
+			return fmt.Errorf("%w: %s resolve para %s (excluído)", ErrForaDoEscopo, host, ip)
+		}
+	}
+	if escopoPermitidos.contemHost(host) {
+		return nil
+	}
+	// Pelas redes, todos os IPs do host precisam estar no escopo
+	if escopoPermitidos.contemTodosIPs(ips) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s não está entre os permitidos", ErrForaDoEscopo, host)
+}
+
+// escopoTransport bloqueia as requisições fora do escopo antes de qualquer acesso à rede
+// e registra todas as requisições (inclusive as bloqueadas) no log de auditoria.
+type escopoTransport struct {
+	base http.RoundTripper
+}
+
+// EnvolverTransporte aplica a verificação de escopo, a auditoria e a gravação/reprodução (HTTP_MODO) a um
+// transporte HTTP. Todo cliente que acessa a rede (inclusive os de checagens com cliente próprio) deve
+// usar o transporte envolvido. Transportes sem discagem TLS própria passam a discar por discar (DNS
+// configurado e redes do escopo conferidas no IP conectado), sem proxy, como o cliente principal.
+func EnvolverTransporte(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t, ok := base.(*http.Transport); ok && t.DialTLS == nil && t.DialTLSContext == nil {
+		t = t.Clone()
+		t.DialContext = discar
+		t.Proxy = nil
+		base = t
+	}
+	return &escopoTransport{base: envolverCassete(base)}
+}
+
+func (e *escopoTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	inicio := time.Now()
+	if err := VerificarEscopo(req.URL.Hostname()); err != nil {
+		registrarBloqueio(req, err)
+		registrarAuditoria(registroAuditoria{Hora: horaAuditoria(inicio), Metodo: req.Method, URL: req.URL.String(), Status: "bloqueada: " + err.Error()})
+		return nil, err
+	}
+	resp, err := e.base.RoundTrip(req)
+	auditarResposta(req, resp, err, inicio)
+	return resp, err
+}
+
+// registrarBloqueio salva toda requisição bloqueada em escopo-bloqueados.txt (avisa uma vez por host).
+func registrarBloqueio(req *http.Request, motivo error) {
+	LogSave(fmt.Sprintf("%s - %s %s - %v", time.Now().Format(time.RFC3339), req.Method, req.URL.String(), motivo), "escopo-bloqueados.txt")
+	if _, avisado := hostsBloqueados.LoadOrStore(req.URL.Hostname(), true); !avisado {
+		Warning("Requisição bloqueada (fora do escopo): %s %s", req.Method, req.URL.String())
+	}
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegrasEscopoContemHost(t *testing.T) {
+	regras, err := compilarEscopo([]string{"Exemplo.com.", "*.cliente.com.br", " api.outro.io ", "10.0.0.0/8", "192.168.1.10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	casos := []struct {
+		host     string
+		esperado bool
+	}{
+		{"exemplo.com", true},
+		{"www.exemplo.com", false}, // domínio exato não inclui subdomínios
+		{"cliente.com.br", false},  // o curinga não inclui o próprio domínio
+		{"loja.cliente.com.br", true},
+		{"a.b.cliente.com.br", true},
+		{"falsocliente.com.br", false},
+		{"cliente.com.br.atacante.com", false},
+		{"api.outro.io", true},
+		{"outro.io", false},
+		{"10.1.2.3", false}, // redes são conferidas por contemIP, não por contemHost
+		{"", false},
+	}
+	for _, c := range casos {
+		if got := regras.contemHost(c.host); got != c.esperado {
+			t.Errorf("contemHost(%q) = %v, esperado %v", c.host, got, c.esperado)
+		}
+	}
+	if len(regras.redes) != 2 {
+		t.Errorf("%d redes compiladas, esperado 2", len(regras.redes))
+	}
+	if _, err := compilarEscopo([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("CIDR inválido aceito")
+	}
+}
+
+// usarEscopo define o escopo no teste e restaura o anterior (e o diretório de trabalho, com ./retornos) no fim.
+func usarEscopo(t *testing.T, permitidos, excluidos []string) {
+	t.Helper()
+	ativo, perm, excl := escopoAtivo, escopoPermitidos, escopoExcluidos
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmp := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmp, "retornos"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(tmp); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		escopoAtivo, escopoPermitidos, escopoExcluidos = ativo, perm, excl
+		os.Chdir(dir)
+	})
+	if err := DefinirEscopo(permitidos, excluidos); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiscarConfereRedesNoIPDiscado(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	addr := ln.Addr().String()
+
+	casos := []struct {
+		nome                  string
+		permitidos, excluidos []string
+		bloqueado             bool
+	}{
+		{"rede permitida", []string{"127.0.0.0/8"}, nil, false},
+		{"fora das redes permitidas", []string{"10.0.0.0/8"}, nil, true},
+		{"IP excluído", []string{"127.0.0.0/8"}, []string{"127.0.0.1"}, true},
+		{"permitido por domínio", []string{"exemplo.com", "10.0.0.0/8"}, nil, true}, // o domínio não cobre o IP discado
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			usarEscopo(t, c.permitidos, c.excluidos)
+			conn, err := discar(context.Background(), "tcp", addr)
+			if conn != nil {
+				conn.Close()
+			}
+			if bloqueado := errors.Is(err, ErrForaDoEscopo); bloqueado != c.bloqueado {
+				t.Fatalf("discar(%s) = %v, bloqueio esperado: %v", addr, err, c.bloqueado)
+			}
+			if !c.bloqueado && err != nil {
+				t.Fatalf("discar(%s) = %v", addr, err)
+			}
+		})
+	}
+}
+
+func TestEnvolverTransporteDiscaPeloEscopo(t *testing.T) {
+	usarEscopo(t, []string{"10.0.0.0/8"}, nil)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	// Transporte próprio de uma checagem (como TimThumb e Firebase): a discagem também confere o IP
+	envolvido, ok := EnvolverTransporte(&http.Transport{}).(*escopoTransport).base.(*http.Transport)
+	if !ok || envolvido.DialContext == nil {
+		t.Fatal("transporte envolvido sem a discagem do escopo")
+	}
+	conn, err := envolvido.DialContext(context.Background(), "tcp", ln.Addr().String())
+	if conn != nil {
+		conn.Close()
+	}
+	if !errors.Is(err, ErrForaDoEscopo) {
+		t.Fatalf("discagem de %s = %v, esperado ErrForaDoEscopo", ln.Addr(), err)
+	}
+}
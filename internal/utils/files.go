@@ -42,6 +42,22 @@ func CreateFolders() {
 			return
 		}
 	}
+	// Pasta de arquivos rastreados por controle de versão (.git exposto)
+	if _, err := os.Stat("./retornos/vcs"); os.IsNotExist(err) {
+		err := os.Mkdir("./retornos/vcs", 0755)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	// Pasta de arquivos enumerados por listagens de diretório abertas
+	if _, err := os.Stat("./retornos/listagem"); os.IsNotExist(err) {
+		err := os.Mkdir("./retornos/listagem", 0755)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
 }
 
 // CarregarListas lê um arquivo .txt (uma string por linha) e retorna slice.
@@ -0,0 +1,282 @@
+// internal\utils\gravacao.go
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// interacaoGravada representa um par requisição/resposta salvo no cassete (uma linha JSON por interação).
+// O corpo é salvo em []byte (base64 no JSON) para que a reprodução seja byte a byte.
+type interacaoGravada struct {
+	Metodo     string      `json:"metodo"`
+	URL        string      `json:"url"`
+	Faixa      string      `json:"faixa,omitempty"`        // cabeçalho Range da requisição
+	HashCorpo  string      `json:"corpo_sha256,omitempty"` // SHA-256 (8 bytes) do corpo enviado
+	Status     int         `json:"status,omitempty"`
+	Cabecalhos http.Header `json:"cabecalhos,omitempty"`
+	Corpo      []byte      `json:"corpo,omitempty"`
+	// Incompleto indica que o corpo foi fechado antes do fim: só os bytes lidos foram gravados,
+	// e Tamanho guarda o Content-Length original.
+	Incompleto bool   `json:"incompleto,omitempty"`
+	Tamanho    int64  `json:"tamanho,omitempty"`
+	Erro       string `json:"erro,omitempty"`
+}
+
+// limiteGravacao é o máximo de bytes de corpo gravados por interação.
+const limiteGravacao = 64 * 1024 * 1024
+
+var (
+	// Gravação e reprodução configuradas por HTTP_MODO; EnvolverTransporte as aplica a todos os clientes.
+	casseteGravacao   *arquivoCassete
+	casseteReproducao *reprodutorTransport
+)
+
+// chaveInteracao identifica uma interação pelo método, URL, faixa pedida (Range) e hash do corpo enviado:
+// um GET parcial e um GET completo da mesma URL, ou POSTs com corpos diferentes, são interações diferentes.
+func chaveInteracao(metodo, url, faixa, hashCorpo string) string {
+	chave := metodo + " " + url
+	if faixa != "" {
+		chave += " range=" + faixa
+	}
+	if hashCorpo != "" {
+		chave += " corpo=" + hashCorpo
+	}
+	return chave
+}
+
+// hashCorpoRequisicao retorna o hash do corpo da requisição (vazio se não houver corpo).
+func hashCorpoRequisicao(req *http.Request) string {
+	if req.Body == nil || req.GetBody == nil {
+		return ""
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer rc.Close()
+	corpo, err := io.ReadAll(rc)
+	if err != nil || len(corpo) == 0 {
+		return ""
+	}
+	soma := sha256.Sum256(corpo)
+	return hex.EncodeToString(soma[:8])
+}
+
+// envolverCassete aplica a gravação ou a reprodução (se configuradas) ao transporte.
+func envolverCassete(base http.RoundTripper) http.RoundTripper {
+	switch {
+	case casseteReproducao != nil:
+		return casseteReproducao
+	case casseteGravacao != nil:
+		return &gravadorTransport{base: base, cassete: casseteGravacao}
+	}
+	return base
+}
+
+// arquivoCassete é o cassete em gravação, compartilhado por todos os transportes. Com uma cifra, cada
+// linha é gravada cifrada (mesmo formato do arquivo de evidências), já que os corpos contêm os segredos.
+type arquivoCassete struct {
+	arquivo *os.File
+	cifra   cipher.AEAD
+	mu      sync.Mutex
+}
+
+// novoCassete abre (ou cria) o cassete em modo append, legível só pelo dono.
+func novoCassete(caminho string, cifra cipher.AEAD) (*arquivoCassete, error) {
+	f, err := os.OpenFile(caminho, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir cassete %s: %w", caminho, err)
+	}
+	// Um cassete criado por uma versão anterior pode estar com permissões abertas
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("erro ao restringir as permissões do cassete %s: %w", caminho, err)
+	}
+	return &arquivoCassete{arquivo: f, cifra: cifra}, nil
+}
+
+// salvar escreve a interação como uma linha JSON (ou cifrada) no cassete.
+func (c *arquivoCassete) salvar(interacao interacaoGravada) {
+	linha, err := json.Marshal(interacao)
+	if err != nil {
+		return
+	}
+	if c.cifra != nil {
+		cifrada, err := cifrarLinha(c.cifra, linha)
+		if err != nil {
+			Error("Erro ao cifrar a interação do cassete: %v", err)
+			return
+		}
+		linha = []byte(cifrada)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.arquivo.Write(append(linha, '\n'))
+}
+
+// gravadorTransport repassa as requisições ao transporte real e salva cada interação no cassete.
+type gravadorTransport struct {
+	base    http.RoundTripper
+	cassete *arquivoCassete
+}
+
+func (g *gravadorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	interacao := interacaoGravada{
+		Metodo:    req.Method,
+		URL:       req.URL.String(),
+		Faixa:     req.Header.Get("Range"),
+		HashCorpo: hashCorpoRequisicao(req),
+	}
+
+	resp, err := g.base.RoundTrip(req)
+	if err != nil {
+		// Erros também são gravados, para que a reprodução falhe da mesma forma.
+		interacao.Erro = err.Error()
+		g.cassete.salvar(interacao)
+		return nil, err
+	}
+
+	interacao.Status = resp.StatusCode
+	interacao.Cabecalhos = resp.Header
+	interacao.Tamanho = resp.ContentLength
+	// O corpo é gravado à medida que o chamador o lê (a leitura continua em streaming)
+	resp.Body = &corpoGravado{ReadCloser: resp.Body, cassete: g.cassete, interacao: interacao}
+	return resp, nil
+}
+
+// corpoGravado copia os bytes lidos do corpo (até limiteGravacao) e grava a interação no fechamento.
+type corpoGravado struct {
+	io.ReadCloser
+	cassete   *arquivoCassete
+	interacao interacaoGravada
+	lidos     bytes.Buffer
+	completo  bool
+	once      sync.Once
+}
+
+func (c *corpoGravado) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	if espaco := limiteGravacao - c.lidos.Len(); espaco > 0 {
+		c.lidos.Write(p[:min(n, espaco)])
+	}
+	if err == io.EOF {
+		c.completo = true
+	} else if err != nil {
+		c.interacao.Erro = err.Error()
+	}
+	return n, err
+}
+
+func (c *corpoGravado) Close() error {
+	err := c.ReadCloser.Close()
+	c.once.Do(func() {
+		c.interacao.Corpo = c.lidos.Bytes()
+		c.interacao.Incompleto = !c.completo
+		c.cassete.salvar(c.interacao)
+	})
+	return err
+}
+
+// reprodutorTransport responde às requisições a partir do cassete, sem acessar a rede.
+type reprodutorTransport struct {
+	interacoes map[string][]interacaoGravada
+	posicao    map[string]int
+	mu         sync.Mutex
+}
+
+// novoReprodutor carrega todas as interações do cassete em memória. Linhas cifradas são abertas com a cifra
+// (EVIDENCIAS_CHAVE); sem ela, um cassete cifrado não pode ser reproduzido.
+func novoReprodutor(caminho string, cifra cipher.AEAD) (*reprodutorTransport, error) {
+	f, err := os.Open(caminho)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir cassete %s: %w", caminho, err)
+	}
+	defer f.Close()
+
+	r := &reprodutorTransport{
+		interacoes: make(map[string][]interacaoGravada),
+		posicao:    make(map[string]int),
+	}
+	scanner := bufio.NewScanner(f)
+	// Os corpos podem ser grandes, então aumentamos o buffer máximo por linha.
+	scanner.Buffer(make([]byte, 0, 64*1024), 512*1024*1024)
+	for scanner.Scan() {
+		linha := bytes.TrimSpace(scanner.Bytes())
+		if len(linha) == 0 {
+			continue
+		}
+		if linha[0] != '{' {
+			if cifra == nil {
+				return nil, fmt.Errorf("cassete %s cifrado: defina EVIDENCIAS_CHAVE para reproduzi-lo", caminho)
+			}
+			decifrada, err := DecifrarEvidencia(cifra, string(linha))
+			if err != nil {
+				return nil, fmt.Errorf("erro ao decifrar o cassete %s: %w", caminho, err)
+			}
+			linha = decifrada
+		}
+		var interacao interacaoGravada
+		if err := json.Unmarshal(linha, &interacao); err != nil {
+			continue
+		}
+		chave := chaveInteracao(interacao.Metodo, interacao.URL, interacao.Faixa, interacao.HashCorpo)
+		r.interacoes[chave] = append(r.interacoes[chave], interacao)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler cassete %s: %w", caminho, err)
+	}
+	return r, nil
+}
+
+func (r *reprodutorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	chave := chaveInteracao(req.Method, req.URL.String(), req.Header.Get("Range"), hashCorpoRequisicao(req))
+
+	r.mu.Lock()
+	lista := r.interacoes[chave]
+	if len(lista) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("interação não gravada no cassete: %s", chave)
+	}
+	// Interações repetidas são servidas na ordem gravada; a última se repete.
+	idx := r.posicao[chave]
+	if idx < len(lista)-1 {
+		r.posicao[chave] = idx + 1
+	}
+	interacao := lista[idx]
+	r.mu.Unlock()
+
+	if interacao.Erro != "" && interacao.Status == 0 {
+		return nil, errors.New(interacao.Erro)
+	}
+
+	cabecalhos := interacao.Cabecalhos
+	if cabecalhos == nil {
+		cabecalhos = make(http.Header)
+	}
+	tamanho := int64(len(interacao.Corpo))
+	if interacao.Incompleto {
+		tamanho = interacao.Tamanho
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interacao.Status, http.StatusText(interacao.Status)),
+		StatusCode:    interacao.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        cabecalhos.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(interacao.Corpo)),
+		ContentLength: tamanho,
+		Request:       req,
+	}, nil
+}
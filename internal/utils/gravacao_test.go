@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// usarCassete troca o transporte do cliente global pelo da gravação ou reprodução, sem cache e sem auditoria.
+func usarCassete(t *testing.T, gravacao *arquivoCassete, reproducao *reprodutorTransport) {
+	t.Helper()
+	transporte, cache, auditoria := client.Transport, cacheHabilitado, auditoriaHabilitada
+	casseteGravacao, casseteReproducao = gravacao, reproducao
+	cacheHabilitado, auditoriaHabilitada = false, false
+	client.Transport = EnvolverTransporte(newHTTPTransport())
+	t.Cleanup(func() {
+		client.Transport, cacheHabilitado, auditoriaHabilitada = transporte, cache, auditoria
+		casseteGravacao, casseteReproducao = nil, nil
+	})
+}
+
+func TestCasseteGravaEReproduz(t *testing.T) {
+	cifra, err := NovaCifraEvidencias(strings.Repeat("ab", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		nome  string
+		cifra cipher.AEAD
+	}{{"texto puro", nil}, {"cifrado", cifra}} {
+		t.Run(c.nome, func(t *testing.T) { testarCassete(t, c.cifra) })
+	}
+}
+
+func testarCassete(t *testing.T, cifra cipher.AEAD) {
+	conteudo := strings.Repeat("0123456789", 100)
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			var corpo bytes.Buffer
+			corpo.ReadFrom(r.Body)
+			w.Write([]byte("eco:" + corpo.String()))
+			return
+		}
+		http.ServeContent(w, r, "arquivo.txt", time.Time{}, strings.NewReader(conteudo))
+	}))
+	defer servidor.Close()
+	url := servidor.URL + "/arquivo.txt"
+	caminho := filepath.Join(t.TempDir(), "cassete.jsonl")
+
+	gravacao, err := novoCassete(caminho, cifra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usarCassete(t, gravacao, nil)
+	if _, err := GetPrefixo(url, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetBody(url); err != nil {
+		t.Fatal(err)
+	}
+	for _, corpo := range []string{"a", "b"} {
+		if _, err := Enviar(servidor.URL+"/post", "text/plain", []byte(corpo)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gravacao.arquivo.Close()
+
+	info, err := os.Stat(caminho)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("cassete com permissões %v, esperado 0600", info.Mode().Perm())
+	}
+	gravado, _ := os.ReadFile(caminho)
+	if cifra != nil && bytes.Contains(gravado, []byte("eco:")) {
+		t.Error("cassete cifrado contém o corpo em texto puro")
+	}
+	if cifra != nil {
+		if _, err := novoReprodutor(caminho, nil); err == nil {
+			t.Error("cassete cifrado reproduzido sem a chave")
+		}
+	}
+
+	reproducao, err := novoReprodutor(caminho, cifra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	usarCassete(t, nil, reproducao)
+	servidor.Close()
+
+	// Ordem diferente da gravação: cada pedido recebe a própria interação
+	casos := []struct {
+		nome   string
+		buscar func() (*Resposta, error)
+		status int
+		corpo  string
+	}{
+		{"POST b", func() (*Resposta, error) { return Enviar(servidor.URL+"/post", "text/plain", []byte("b")) }, 200, "eco:b"},
+		{"GET completo", func() (*Resposta, error) { return Buscar(url) }, 200, conteudo},
+		{"GET parcial", func() (*Resposta, error) { return GetPrefixo(url, 10) }, 206, conteudo[:10]},
+		{"POST a", func() (*Resposta, error) { return Enviar(servidor.URL+"/post", "text/plain", []byte("a")) }, 200, "eco:a"},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			resp, err := c.buscar()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != c.status || string(resp.Body) != c.corpo {
+				t.Errorf("status %d corpo %q, esperado %d %q", resp.StatusCode, resp.Body, c.status, c.corpo)
+			}
+		})
+	}
+}
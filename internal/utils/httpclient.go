@@ -1,12 +1,20 @@
 package utils
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
 	"crypto/tls" // apenas para constantes e compatibilidade; não usamos o handshake padrão
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	browser "github.com/EDDYCJY/fake-useragent"
@@ -19,10 +27,13 @@ func init() {
 	log.SetOutput(io.Discard)
 }
 
-// dialTLS utiliza uTLS para criar uma conexão TLS customizada, imitando o handshake do Chrome.
+// dialTLS utiliza uTLS para criar uma conexão TLS customizada, imitando o handshake de um navegador
+// (perfil definido em TLS_PERFIL) e registrando os metadados do certificado de cada host.
 func dialTLS(network, addr string) (net.Conn, error) {
-	// Estabelece conexão TCP com timeout.
-	conn, err := net.DialTimeout(network, addr, 5*time.Second)
+	// Estabelece conexão TCP com timeout (usando a resolução DNS configurada).
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := discar(ctx, network, addr)
 	if err != nil {
 		return nil, err
 	}
@@ -34,8 +45,10 @@ func dialTLS(network, addr string) (net.Conn, error) {
 	}
 
 	// Configuração uTLS com definição explícita dos cipher suites.
+	// Sem TLS_VERIFICAR=true o certificado não é validado no handshake (apenas registrado).
 	utlsConfig := &utls.Config{
-		InsecureSkipVerify: true, // Use com cautela!
+		InsecureSkipVerify: !verificarTLS,
+		RootCAs:            raizesTLS,
 		ServerName:         host,
 		CipherSuites: []uint16{
 			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
@@ -47,11 +60,12 @@ func dialTLS(network, addr string) (net.Conn, error) {
 		},
 	}
 
-	// Se utls.HelloChrome_112 não estiver disponível, utilize utls.HelloChrome_118 ou outro preset disponível.
-	uConn := utls.UClient(conn, utlsConfig, utls.HelloChrome_Auto)
+	uConn := utls.UClient(conn, utlsConfig, clientHelloPara(host))
 	if err := uConn.Handshake(); err != nil {
+		conn.Close()
 		return nil, err
 	}
+	registrarMetadadosTLS(host, uConn.ConnectionState())
 	return uConn, nil
 }
 
@@ -59,11 +73,8 @@ func dialTLS(network, addr string) (net.Conn, error) {
 // e configurado para suportar HTTP/2.
 func newHTTPTransport() *http.Transport {
 	transport := &http.Transport{
-		// DialContext para conexões não-TLS (caso necessário).
-		DialContext: (&net.Dialer{
-			Timeout:   10 * time.Second,
-			KeepAlive: 30 * time.Second,
-		}).DialContext,
+		// DialContext para conexões não-TLS (caso necessário), com a resolução DNS configurada.
+		DialContext: discar,
 		// Substitui o DialTLS padrão pela nossa implementação com uTLS.
 		DialTLS: dialTLS,
 		// Timeouts e configurações do transporte.
@@ -95,6 +106,90 @@ var client = &http.Client{
 	},
 }
 
+// ConfigurarHTTP lê as variáveis de ambiente do cliente HTTP e monta o transporte final.
+// Deve ser chamada depois que o .env for carregado e depois de ConfigurarRedacao (cifra do cassete).
+//
+//	HTTP_MODO=gravar      -> grava todas as interações no cassete
+//	HTTP_MODO=reproduzir  -> responde a partir do cassete, sem acessar a rede
+//	HTTP_CASSETE=arquivo  -> caminho do cassete (padrão ./retornos/cassete.jsonl), cifrado com EVIDENCIAS_CHAVE
+//	HTTP_CASSETE_TEXTO_PURO=true -> permite gravar o cassete sem cifra com a redação de segredos ligada
+//	CACHE_HTTP=false      -> desativa o cache de respostas por alvo
+//	CACHE_TAMANHO_MAXIMO=bytes -> maior corpo mantido no cache (padrão 1 MiB)
+//	TLS_PERFIL=perfil     -> ClientHello: chrome, firefox, safari, go ou random (um por host)
+//	TLS_VERIFICAR=true    -> valida a cadeia de certificados (TLS_CA_BUNDLE=arquivo.pem para CAs próprias)
+//	HTTP_CONFIG=arquivo   -> cabeçalhos, cookies, basic auth e user agent globais ou por alvo (YAML)
+//	MAX_BODY=bytes        -> tamanho máximo de corpo lido (MAX_BODY_<CHECAGEM> para uma checagem específica)
+//	DNS_SERVIDOR, DNS_DOH, DNS_HOSTS, DNS_PRE_RESOLVER -> resolução DNS (ver dns.go)
+//	ESCOPO_ARQUIVO=arquivo -> domínios, curingas e CIDRs permitidos/excluídos (ver escopo.go)
+//	AUDITORIA=false        -> desativa o log de auditoria (AUDITORIA_ARQUIVO, padrão ./retornos/auditoria.jsonl)
+func ConfigurarHTTP() error {
+	if err := configurarDNS(); err != nil {
+		return err
+	}
+	if err := configurarEscopo(); err != nil {
+		return err
+	}
+	if err := configurarAuditoria(); err != nil {
+		return err
+	}
+	if val := os.Getenv("MAX_BODY"); val != "" {
+		if n, err := strconv.ParseInt(val, 10, 64); err == nil && n > 0 {
+			maxBody = n
+		}
+	}
+	if err := configurarTLS(); err != nil {
+		return err
+	}
+	if err := carregarConfigHTTP(); err != nil {
+		return err
+	}
+	if val := os.Getenv("CACHE_HTTP"); val != "" {
+		cacheHabilitado = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("CACHE_TAMANHO_MAXIMO"); val != "" {
+		if n, err := strconv.Atoi(val); err == nil && n >= 0 {
+			tamanhoMaximoCache = n
+		}
+	}
+
+	cassete := os.Getenv("HTTP_CASSETE")
+	if cassete == "" {
+		cassete = "./retornos/cassete.jsonl"
+	}
+
+	switch strings.ToLower(os.Getenv("HTTP_MODO")) {
+	case "gravar", "record":
+		// O cassete guarda os corpos completos (.env, backups, dumps): com a redação ligada, só é gravado
+		// cifrado, a não ser que o usuário aceite explicitamente o texto puro
+		if cifraEvidencias == nil && redigirSegredos && strings.ToLower(os.Getenv("HTTP_CASSETE_TEXTO_PURO")) != "true" {
+			return errors.New("o cassete guarda as respostas completas, com os segredos: defina EVIDENCIAS_CHAVE para cifrá-lo ou HTTP_CASSETE_TEXTO_PURO=true para gravá-lo em texto puro")
+		}
+		gravacao, err := novoCassete(cassete, cifraEvidencias)
+		if err != nil {
+			return err
+		}
+		casseteGravacao = gravacao
+		if cifraEvidencias != nil {
+			Info("Gravando tráfego HTTP em %s (cifrado com EVIDENCIAS_CHAVE)", cassete)
+		} else {
+			Warning("Gravando tráfego HTTP em %s em texto puro (HTTP_CASSETE_TEXTO_PURO=true)", cassete)
+		}
+	case "reproduzir", "replay":
+		reprodutor, err := novoReprodutor(cassete, cifraEvidencias)
+		if err != nil {
+			return err
+		}
+		casseteReproducao = reprodutor
+		// A reprodução não acessa a rede: a pré-resolução DNS descartaria alvos que deixaram de resolver
+		PreResolverDNS = false
+		Info("Reproduzindo tráfego HTTP de %s (sem acesso à rede)", cassete)
+	}
+	// O escopo é verificado antes de tudo, inclusive da gravação e da reprodução (aplicadas por
+	// EnvolverTransporte também aos clientes próprios das checagens)
+	client.Transport = EnvolverTransporte(client.Transport)
+	return nil
+}
+
 // setDefaultHeaders adiciona cabeçalhos para simular um navegador real, com Client Hints e outros.
 func setDefaultHeaders(req *http.Request) {
 	req.Header.Set("User-Agent", browser.Computer())
@@ -120,55 +215,187 @@ func setDefaultHeaders(req *http.Request) {
 	req.Header.Set("Sec-CH-UA", `"Chromium";v="112", "Google Chrome";v="112", "Not:A-Brand";v="99"`)
 	req.Header.Set("Sec-CH-UA-Mobile", "?0")
 	req.Header.Set("Sec-CH-UA-Platform", `"Windows"`)
+	// Cabeçalhos, cookies e credenciais configurados em HTTP_CONFIG
+	aplicarConfigHTTP(req)
 }
 
-// TestURL faz uma requisição HEAD e retorna true se o status code estiver entre 200 e 399.
-// Se a requisição HEAD falhar (por exemplo, se o servidor não suportar HEAD), tenta GET como fallback.
-func TestURL(url string) bool {
-	req, err := http.NewRequest("HEAD", url, nil)
+// Resposta guarda o resultado já lido de uma requisição, para ser compartilhado entre as checagens.
+type Resposta struct {
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Tipo é o tipo do conteúdo detectado pelos primeiros bytes (ver DetectarTipo).
+	Tipo string
+	// Tamanho é o tamanho total informado pelo servidor (Content-Length ou Content-Range), -1 se desconhecido.
+	Tamanho int64
+	// Truncado indica que o corpo não foi lido por completo (limite atingido ou conteúdo binário).
+	Truncado bool
+
+	limite int64
+}
+
+// pedido descreve como uma requisição deve ser feita e até onde o corpo deve ser lido.
+type pedido struct {
+	metodo string
+	url    string
+	// limite é o máximo de bytes lidos do corpo.
+	limite int64
+	// prefixo pede apenas o início do arquivo (Range) e aceita conteúdo binário.
+	prefixo bool
+	// corpo e tipoCorpo são enviados em requisições POST.
+	corpo     []byte
+	tipoCorpo string
+}
+
+// chave identifica o pedido no cache.
+func (p pedido) chave() string {
+	chave := p.metodo + " " + p.url
+	if p.prefixo {
+		chave += "#prefixo"
+	}
+	// POSTs para a mesma URL com corpos diferentes são respostas diferentes
+	if len(p.corpo) > 0 {
+		soma := sha256.Sum256(p.corpo)
+		chave += "#" + hex.EncodeToString(soma[:8])
+	}
+	return chave
+}
+
+// requisitar executa a requisição no cliente global e lê o corpo em streaming: os primeiros bytes
+// são inspecionados (tipo do conteúdo) e a leitura para ao atingir o limite, ou logo no início
+// quando uma checagem de texto recebe um arquivo binário.
+func requisitar(p pedido) (*Resposta, error) {
+	var corpo io.Reader
+	if p.corpo != nil {
+		corpo = bytes.NewReader(p.corpo)
+	}
+	req, err := http.NewRequest(p.metodo, p.url, corpo)
 	if err != nil {
-		return false
+		return nil, err
 	}
 	setDefaultHeaders(req)
-
+	if p.tipoCorpo != "" {
+		req.Header.Set("Content-Type", p.tipoCorpo)
+	}
+	if p.prefixo {
+		req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", p.limite-1))
+	}
 	resp, err := client.Do(req)
 	if err != nil {
-		// Fallback: tenta GET se HEAD falhar.
-		req, err = http.NewRequest("GET", url, nil)
-		if err != nil {
-			return false
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	resposta := &Resposta{
+		URL:        p.url,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Tamanho:    tamanhoTotal(resp),
+		limite:     p.limite,
+	}
+
+	// Lê os primeiros bytes para identificar o tipo do conteúdo
+	inicio := make([]byte, min(tamanhoSniff, p.limite))
+	n, err := io.ReadFull(resp.Body, inicio)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	inicio = inicio[:n]
+	resposta.Tipo = DetectarTipo(inicio)
+
+	// Checagens de texto não precisam baixar arquivos binários
+	if !p.prefixo && TipoBinario(resposta.Tipo) {
+		resposta.Body = inicio
+		resposta.Truncado = true
+		return resposta, nil
+	}
+
+	// Lê o restante até o limite (+1 byte para saber se passou do limite)
+	resto, err := io.ReadAll(io.LimitReader(resp.Body, p.limite-int64(n)+1))
+	if err != nil {
+		return nil, err
+	}
+	data := append(inicio, resto...)
+	if int64(len(data)) > p.limite {
+		data = data[:p.limite]
+		resposta.Truncado = true
+	}
+	resposta.Body = data
+	return resposta, nil
+}
+
+// tamanhoTotal retorna o tamanho do recurso a partir do Content-Range (respostas 206) ou Content-Length.
+func tamanhoTotal(resp *http.Response) int64 {
+	if cr := resp.Header.Get("Content-Range"); cr != "" {
+		if idx := strings.LastIndex(cr, "/"); idx != -1 {
+			if total, err := strconv.ParseInt(cr[idx+1:], 10, 64); err == nil {
+				return total
+			}
 		}
-		setDefaultHeaders(req)
-		resp, err = client.Do(req)
+	}
+	return resp.ContentLength
+}
+
+// TestURL faz uma requisição HEAD e retorna true se o status code estiver entre 200 e 399.
+// Se a requisição HEAD falhar (por exemplo, se o servidor não suportar HEAD), tenta GET como fallback.
+func TestURL(url string) bool {
+	resp, err := buscarComCache(pedido{metodo: "HEAD", url: url, limite: maxBody})
+	if err != nil {
+		// Fallback: tenta GET se HEAD falhar.
+		resp, err = Buscar(url)
 		if err != nil {
 			return false
 		}
 	}
-	defer resp.Body.Close()
 	return resp.StatusCode >= 200 && resp.StatusCode < 400
 }
 
+// Buscar faz um GET (com cache) e retorna a resposta completa, incluindo status e cabeçalhos.
+// O corpo é lido até o limite padrão (MAX_BODY).
+func Buscar(url string) (*Resposta, error) {
+	return buscarComCache(pedido{metodo: "GET", url: url, limite: maxBody})
+}
+
+// BuscarHead faz um HEAD (com cache), sem fallback para GET, e retorna status e cabeçalhos.
+func BuscarHead(url string) (*Resposta, error) {
+	return buscarComCache(pedido{metodo: "HEAD", url: url, limite: maxBody})
+}
+
 // GetBody retorna o conteúdo da URL se o status code for 200.
 // Caso o status não seja 200, retorna um erro.
 func GetBody(url string) (string, error) {
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return "", err
-	}
-	setDefaultHeaders(req)
-	resp, err := client.Do(req)
+	return GetBodyLimitado(url, maxBody)
+}
+
+// GetBodyLimitado funciona como GetBody, mas com um limite próprio de bytes (ver LimiteBody).
+// Corpos maiores que o limite ou conteúdos binários retornam erro.
+func GetBodyLimitado(url string, limite int64) (string, error) {
+	resp, err := buscarComCache(pedido{metodo: "GET", url: url, limite: limite})
 	if err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-
 	if resp.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("status code %d", resp.StatusCode)
 	}
-
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
+	if TipoBinario(resp.Tipo) {
+		return "", fmt.Errorf("conteúdo binário (%s)", resp.Tipo)
 	}
-	return string(data), nil
+	if resp.Truncado {
+		return "", fmt.Errorf("corpo excede o limite de %d bytes", limite)
+	}
+	return string(resp.Body), nil
+}
+
+// Enviar faz um POST com o corpo e o Content-Type informados (com cache por URL e corpo)
+// e retorna a resposta completa. O corpo da resposta é lido até o limite padrão (MAX_BODY).
+func Enviar(url, tipoConteudo string, corpo []byte) (*Resposta, error) {
+	return buscarComCache(pedido{metodo: "POST", url: url, limite: maxBody, corpo: corpo, tipoCorpo: tipoConteudo})
+}
+
+// GetPrefixo busca apenas os primeiros n bytes da URL (com Range), sem baixar o arquivo inteiro.
+// Serve para inspecionar arquivos possivelmente grandes, como dumps SQL ou backups compactados.
+// O chamador deve verificar o StatusCode (200 ou 206).
+func GetPrefixo(url string, n int64) (*Resposta, error) {
+	return buscarComCache(pedido{metodo: "GET", url: url, limite: n, prefixo: true})
 }
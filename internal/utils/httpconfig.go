@@ -0,0 +1,124 @@
+// internal\utils\httpconfig.go
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// BasicAuth guarda as credenciais HTTP Basic de um alvo.
+type BasicAuth struct {
+	Usuario string `yaml:"usuario"`
+	Senha   string `yaml:"senha"`
+}
+
+// ConfigRequisicao agrupa os cabeçalhos, cookies e credenciais extras de um alvo (ou globais).
+type ConfigRequisicao struct {
+	UserAgent string            `yaml:"user_agent"`
+	Headers   map[string]string `yaml:"headers"`
+	Cookies   map[string]string `yaml:"cookies"`
+	BasicAuth *BasicAuth        `yaml:"basic_auth"`
+}
+
+// arquivoConfigHTTP é o formato do arquivo indicado em HTTP_CONFIG.
+type arquivoConfigHTTP struct {
+	CookieJar bool                        `yaml:"cookie_jar"`
+	Global    ConfigRequisicao            `yaml:"global"`
+	Alvos     map[string]ConfigRequisicao `yaml:"alvos"`
+}
+
+// configHTTP é a configuração carregada (nil se HTTP_CONFIG não estiver definido).
+var configHTTP *arquivoConfigHTTP
+
+// carregarConfigHTTP lê o arquivo HTTP_CONFIG (YAML). Exemplo:
+//
+//	cookie_jar: true
+//	global:
+//	  user_agent: "Mozilla/5.0 (scanner interno)"
+//	  headers:
+//	    X-WAF-Bypass: "token"
+//	alvos:
+//	  staging.exemplo.com.br:        # vale também para os subdomínios
+//	    basic_auth:
+//	      usuario: "admin"
+//	      senha: "segredo"
+//	    cookies:
+//	      ambiente: "staging"
+func carregarConfigHTTP() error {
+	caminho := os.Getenv("HTTP_CONFIG")
+	if caminho == "" {
+		return nil
+	}
+	data, err := os.ReadFile(caminho)
+	if err != nil {
+		return fmt.Errorf("erro ao ler HTTP_CONFIG %s: %w", caminho, err)
+	}
+	var cfg arquivoConfigHTTP
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("erro ao interpretar %s: %w", caminho, err)
+	}
+	// Normaliza os hosts para comparação
+	alvos := make(map[string]ConfigRequisicao, len(cfg.Alvos))
+	for host, c := range cfg.Alvos {
+		alvos[strings.ToLower(strings.TrimSpace(host))] = c
+	}
+	cfg.Alvos = alvos
+	configHTTP = &cfg
+
+	if cfg.CookieJar {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return err
+		}
+		client.Jar = jar
+	}
+	Info("Configuração HTTP carregada de %s (%d alvos)", caminho, len(cfg.Alvos))
+	return nil
+}
+
+// configParaHost retorna a configuração específica do host (ou do domínio pai mais próximo).
+func configParaHost(host string) (ConfigRequisicao, bool) {
+	host = strings.ToLower(host)
+	for {
+		if c, ok := configHTTP.Alvos[host]; ok {
+			return c, true
+		}
+		idx := strings.Index(host, ".")
+		if idx == -1 {
+			return ConfigRequisicao{}, false
+		}
+		host = host[idx+1:]
+	}
+}
+
+// aplicarConfigRequisicao aplica user agent, cabeçalhos, cookies e basic auth na requisição.
+func aplicarConfigRequisicao(req *http.Request, c ConfigRequisicao) {
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	for nome, valor := range c.Headers {
+		req.Header.Set(nome, valor)
+	}
+	for nome, valor := range c.Cookies {
+		req.AddCookie(&http.Cookie{Name: nome, Value: valor})
+	}
+	if c.BasicAuth != nil {
+		req.SetBasicAuth(c.BasicAuth.Usuario, c.BasicAuth.Senha)
+	}
+}
+
+// aplicarConfigHTTP aplica a configuração global e, em seguida, a do alvo (que tem prioridade).
+func aplicarConfigHTTP(req *http.Request) {
+	if configHTTP == nil || req.URL == nil {
+		return
+	}
+	aplicarConfigRequisicao(req, configHTTP.Global)
+	if c, ok := configParaHost(req.URL.Hostname()); ok {
+		aplicarConfigRequisicao(req, c)
+	}
+}
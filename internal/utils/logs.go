@@ -41,8 +41,8 @@ func LogSave(texto, filename string) {
 	}
 	defer file.Close()
 
-	// Escreve o texto no arquivo.
-	if _, err := file.WriteString(texto + "\n"); err != nil {
+	// Escreve o texto no arquivo, com os segredos registrados mascarados.
+	if _, err := file.WriteString(Redigir(texto) + "\n"); err != nil {
 		fmt.Println("Erro ao escrever no arquivo:", err)
 	}
 }
@@ -17,25 +17,25 @@ const (
 
 // Info exibe uma mensagem de informação em azul.
 func Info(format string, a ...interface{}) {
-	msg := fmt.Sprintf(format, a...)
+	msg := Redigir(fmt.Sprintf(format, a...))
 	fmt.Printf("%s - %s[INFO]%s %s\n", GetTime(), ColorBlue, ColorReset, msg)
 }
 
 // Ok exibe uma mensagem de sucesso em verde.
 func Ok(format string, a ...interface{}) {
-	msg := fmt.Sprintf(format, a...)
+	msg := Redigir(fmt.Sprintf(format, a...))
 	fmt.Printf("%s - %s[OK]%s %s\n", GetTime(), ColorGreen, ColorReset, msg)
 }
 
 // Warning exibe uma mensagem de aviso em amarelo.
 func Warning(format string, a ...interface{}) {
-	msg := fmt.Sprintf(format, a...)
+	msg := Redigir(fmt.Sprintf(format, a...))
 	fmt.Printf("%s - %s[WARNING]%s %s\n", GetTime(), ColorYellow, ColorReset, msg)
 }
 
 // Error exibe uma mensagem de erro em vermelho.
 func Error(format string, a ...interface{}) {
-	msg := fmt.Sprintf(format, a...)
+	msg := Redigir(fmt.Sprintf(format, a...))
 	fmt.Printf("%s - %s[ERROR]%s %s\n", GetTime(), ColorRed, ColorReset, msg)
 }
 
@@ -0,0 +1,209 @@
+// internal\utils\redacao.go
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tamanhoMinimoRegistro é o menor segredo substituído automaticamente em toda a saída.
+// Valores menores (ex.: senhas "root") poderiam mascarar trechos comuns do texto;
+// eles só são mascarados onde o chamador usa o retorno de RegistrarSegredo.
+const tamanhoMinimoRegistro = 6
+
+var (
+	// redigirSegredos mascara os segredos em toda a saída (REDIGIR_SEGREDOS, padrão true).
+	redigirSegredos = true
+
+	// segredosRegistrados guarda os segredos já vistos e o substituto de cada um em toda a saída.
+	segredosRegistrados = make(map[string]string)
+	substituto          *strings.Replacer
+	muSegredos          sync.RWMutex
+
+	// Arquivo de evidências cifrado (AES-256-GCM), gravado só quando EVIDENCIAS_CHAVE é informada.
+	cifraEvidencias   cipher.AEAD
+	arquivoEvidencias = "./retornos/evidencias.enc"
+	muEvidencias      sync.Mutex
+)
+
+// evidencia é um registro do arquivo de evidências (uma linha cifrada por segredo).
+type evidencia struct {
+	Hora      string `json:"hora"`
+	Origem    string `json:"origem"`
+	Impressao string `json:"impressao"`
+	Valor     string `json:"valor"`
+}
+
+// ConfigurarRedacao lê as variáveis de redação de segredos. Deve ser chamada depois que o .env for carregado.
+//
+//	REDIGIR_SEGREDOS=false   -> grava e exibe os segredos em texto puro
+//	EVIDENCIAS_CHAVE=chave   -> chave AES-256 (64 caracteres hex ou 32 bytes em base64) do arquivo de evidências
+//	EVIDENCIAS_ARQUIVO=arq   -> caminho do arquivo de evidências (padrão ./retornos/evidencias.enc)
+func ConfigurarRedacao() error {
+	if val := os.Getenv("REDIGIR_SEGREDOS"); val != "" {
+		redigirSegredos = strings.ToLower(val) == "true"
+	}
+	if val := os.Getenv("EVIDENCIAS_ARQUIVO"); val != "" {
+		arquivoEvidencias = val
+	}
+	chave := os.Getenv("EVIDENCIAS_CHAVE")
+	if chave == "" {
+		return nil
+	}
+	aead, err := NovaCifraEvidencias(chave)
+	if err != nil {
+		return err
+	}
+	cifraEvidencias = aead
+	return nil
+}
+
+// NovaCifraEvidencias monta a cifra AES-256-GCM a partir da chave (64 caracteres hex ou 32 bytes em base64).
+func NovaCifraEvidencias(chave string) (cipher.AEAD, error) {
+	chave = strings.TrimSpace(chave)
+	bytesChave, err := hex.DecodeString(chave)
+	if err != nil || len(bytesChave) != 32 {
+		bytesChave, err = base64.StdEncoding.DecodeString(chave)
+	}
+	if err != nil || len(bytesChave) != 32 {
+		return nil, errors.New("EVIDENCIAS_CHAVE deve ter 32 bytes (64 caracteres hex ou base64)")
+	}
+	bloco, err := aes.NewCipher(bytesChave)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(bloco)
+}
+
+// ImpressaoDigital retorna os 12 primeiros caracteres do SHA-256 do valor, para correlacionar
+// achados sem expor o segredo.
+func ImpressaoDigital(valor string) string {
+	soma := sha256.Sum256([]byte(valor))
+	return hex.EncodeToString(soma[:])[:12]
+}
+
+// Mascarar mantém só o início e o fim do valor e acrescenta a impressão digital SHA-256.
+// Ex.: "sk_live_abcd1234efgh" -> "sk_l************efgh [sha256:1a2b3c4d5e6f]".
+func Mascarar(valor string) string {
+	runas := []rune(valor)
+	visiveis := 0
+	switch {
+	case len(runas) >= 16:
+		visiveis = 4
+	case len(runas) >= 10:
+		visiveis = 2
+	}
+	mascarado := string(runas[:visiveis]) + strings.Repeat("*", len(runas)-2*visiveis) + string(runas[len(runas)-visiveis:])
+	return fmt.Sprintf("%s [sha256:%s]", mascarado, ImpressaoDigital(valor))
+}
+
+// RegistrarSegredo registra um segredo encontrado: o valor completo vai para o arquivo de evidências
+// cifrado (se configurado) e passa a ser mascarado em toda a saída do terminal e dos retornos.
+// Retorna a forma de exibição do valor (mascarada, ou o próprio valor com REDIGIR_SEGREDOS=false).
+func RegistrarSegredo(valor, origem string) string {
+	if valor == "" {
+		return valor
+	}
+	guardarEvidencia(valor, origem)
+	if !redigirSegredos {
+		return valor
+	}
+	mascarado := Mascarar(valor)
+	if len(valor) < tamanhoMinimoRegistro {
+		return mascarado
+	}
+
+	muSegredos.Lock()
+	defer muSegredos.Unlock()
+	if _, existe := segredosRegistrados[valor]; !existe {
+		segredosRegistrados[valor] = mascarado
+		// Os valores mais longos são substituídos primeiro (um segredo pode conter outro)
+		valores := make([]string, 0, len(segredosRegistrados))
+		for v := range segredosRegistrados {
+			valores = append(valores, v)
+		}
+		sort.Slice(valores, func(i, j int) bool { return len(valores[i]) > len(valores[j]) })
+		pares := make([]string, 0, 2*len(valores))
+		for _, v := range valores {
+			pares = append(pares, v, segredosRegistrados[v])
+		}
+		substituto = strings.NewReplacer(pares...)
+	}
+	return mascarado
+}
+
+// Redigir substitui no texto todos os segredos registrados pela forma mascarada.
+func Redigir(texto string) string {
+	muSegredos.RLock()
+	r := substituto
+	muSegredos.RUnlock()
+	if r == nil {
+		return texto
+	}
+	return r.Replace(texto)
+}
+
+// guardarEvidencia acrescenta o valor completo, cifrado, ao arquivo de evidências.
+// Cada linha é base64(nonce || AES-256-GCM(JSON)).
+func guardarEvidencia(valor, origem string) {
+	if cifraEvidencias == nil {
+		return
+	}
+	registro, err := json.Marshal(evidencia{
+		Hora:      time.Now().Format(time.RFC3339),
+		Origem:    origem,
+		Impressao: ImpressaoDigital(valor),
+		Valor:     valor,
+	})
+	if err != nil {
+		return
+	}
+	linha, err := cifrarLinha(cifraEvidencias, registro)
+	if err != nil {
+		Error("Erro ao cifrar a evidência: %v", err)
+		return
+	}
+
+	muEvidencias.Lock()
+	defer muEvidencias.Unlock()
+	f, err := os.OpenFile(arquivoEvidencias, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		Error("Erro ao abrir o arquivo de evidências: %v", err)
+		return
+	}
+	defer f.Close()
+	f.WriteString(linha + "\n")
+}
+
+// cifrarLinha cifra os dados com AES-256-GCM e retorna base64(nonce || dados cifrados), sem quebras de linha.
+func cifrarLinha(aead cipher.AEAD, dados []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, dados, nil)), nil
+}
+
+// DecifrarEvidencia decifra uma linha do arquivo de evidências e retorna o JSON do registro.
+func DecifrarEvidencia(aead cipher.AEAD, linha string) ([]byte, error) {
+	dados, err := base64.StdEncoding.DecodeString(strings.TrimSpace(linha))
+	if err != nil {
+		return nil, err
+	}
+	if len(dados) < aead.NonceSize() {
+		return nil, errors.New("linha de evidência truncada")
+	}
+	return aead.Open(nil, dados[:aead.NonceSize()], dados[aead.NonceSize():], nil)
+}
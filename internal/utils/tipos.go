@@ -0,0 +1,133 @@
+// internal\utils\tipos.go
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// tamanhoSniff é a quantidade de bytes inspecionada para detectar o tipo do conteúdo.
+const tamanhoSniff = 512
+
+// maxBody é o tamanho máximo padrão (em bytes) de corpo lido por requisição (MAX_BODY).
+var maxBody int64 = 5 * 1024 * 1024
+
+// LimiteBody retorna o limite de corpo da checagem, definido em MAX_BODY_<CHECAGEM>
+// (ex.: MAX_BODY_ENV, MAX_BODY_YAML). Sem a variável, usa MAX_BODY.
+func LimiteBody(checagem string) int64 {
+	if val := os.Getenv("MAX_BODY_" + strings.ToUpper(checagem)); val != "" {
+		if n, err := strconv.ParseInt(val, 10, 64); err == nil && n > 0 {
+			return n
+		}
+	}
+	return maxBody
+}
+
+// assinaturas de arquivos binários (magic bytes) reconhecidas por DetectarTipo.
+var assinaturasBinarias = []struct {
+	tipo   string
+	offset int
+	magic  []byte
+}{
+	{"gzip", 0, []byte{0x1f, 0x8b}},
+	{"zip", 0, []byte("PK\x03\x04")},
+	{"zip", 0, []byte("PK\x05\x06")},
+	{"rar", 0, []byte("Rar!\x1a\x07")},
+	{"7z", 0, []byte{'7', 'z', 0xbc, 0xaf, 0x27, 0x1c}},
+	{"bzip2", 0, []byte("BZh")},
+	{"xz", 0, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}},
+	{"zstd", 0, []byte{0x28, 0xb5, 0x2f, 0xfd}},
+	{"sqlite", 0, []byte("SQLite format 3\x00")},
+	{"pdf", 0, []byte("%PDF-")},
+	{"tar", 257, []byte("ustar")},
+}
+
+// assinaturas de dumps SQL em texto.
+var assinaturasSQL = []string{
+	"-- mysql dump",
+	"-- phpmyadmin sql dump",
+	"-- mariadb dump",
+	"-- postgresql database dump",
+	"-- adminer",
+	"create table",
+	"insert into",
+	"drop table if exists",
+	"/*!40101 set",
+}
+
+// DetectarTipo identifica o conteúdo pelos primeiros bytes. Retorna um dos tipos de
+// assinaturasBinarias, "sql", "html", "json", "xml", "texto" ou "binario".
+func DetectarTipo(inicio []byte) string {
+	for _, a := range assinaturasBinarias {
+		if len(inicio) >= a.offset+len(a.magic) && bytes.Equal(inicio[a.offset:a.offset+len(a.magic)], a.magic) {
+			return a.tipo
+		}
+	}
+	if len(inicio) == 0 {
+		return "texto"
+	}
+
+	if ContemAssinaturaSQL(inicio) {
+		return "sql"
+	}
+	lower := strings.ToLower(string(inicio))
+
+	mime := http.DetectContentType(inicio)
+	switch {
+	case strings.HasPrefix(mime, "text/html"):
+		return "html"
+	case strings.HasPrefix(mime, "text/xml"):
+		return "xml"
+	case strings.HasPrefix(mime, "text/"):
+		trimmed := strings.TrimSpace(lower)
+		if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+			return "json"
+		}
+		return "texto"
+	case mime == "application/json":
+		return "json"
+	}
+	return "binario"
+}
+
+// ContemAssinaturaSQL indica se o texto contém alguma assinatura típica de dump SQL.
+func ContemAssinaturaSQL(conteudo []byte) bool {
+	lower := strings.ToLower(string(conteudo))
+	for _, assinatura := range assinaturasSQL {
+		if strings.Contains(lower, assinatura) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatarTamanho converte bytes em texto legível (ex.: 12.3 MB). Valores negativos são "desconhecido".
+func FormatarTamanho(n int64) string {
+	if n < 0 {
+		return "desconhecido"
+	}
+	unidades := []string{"B", "KB", "MB", "GB", "TB"}
+	valor := float64(n)
+	i := 0
+	for valor >= 1024 && i < len(unidades)-1 {
+		valor /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.1f %s", valor, unidades[i])
+}
+
+// TipoBinario indica se o tipo detectado não é texto.
+func TipoBinario(tipo string) bool {
+	switch tipo {
+	case "texto", "html", "json", "xml", "sql":
+		return false
+	}
+	return true
+}
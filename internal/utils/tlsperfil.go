@@ -0,0 +1,150 @@
+// internal\utils\tlsperfil.go
+package utils
+
+import (
+	"crypto/x509"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	utls "github.com/refraction-networking/utls"
+)
+
+// Perfis de ClientHello disponíveis em TLS_PERFIL.
+var perfisTLS = map[string]utls.ClientHelloID{
+	"chrome":  utls.HelloChrome_Auto,
+	"firefox": utls.HelloFirefox_Auto,
+	"safari":  utls.HelloSafari_Auto,
+	"go":      utls.HelloGolang,
+}
+
+// Perfis sorteados no modo "random" (um por host, mantido durante todo o scan).
+var perfisAleatorios = []string{"chrome", "firefox", "safari"}
+
+var (
+	// perfilTLS é o perfil configurado (chrome, firefox, safari, go ou random).
+	perfilTLS = "chrome"
+	// verificarTLS ativa a verificação estrita de certificados.
+	verificarTLS = false
+	// raizesTLS são as CAs usadas na verificação (nil = CAs do sistema).
+	raizesTLS *x509.CertPool
+
+	perfilPorHost sync.Map // key: host, value: utls.ClientHelloID
+	tlsRegistrado sync.Map // key: host, value: bool (metadados já salvos)
+)
+
+// configurarTLS lê TLS_PERFIL, TLS_VERIFICAR e TLS_CA_BUNDLE.
+func configurarTLS() error {
+	if val := strings.ToLower(os.Getenv("TLS_PERFIL")); val != "" {
+		if _, ok := perfisTLS[val]; !ok && val != "random" {
+			return fmt.Errorf("TLS_PERFIL inválido: %s (use chrome, firefox, safari, go ou random)", val)
+		}
+		perfilTLS = val
+	}
+	if val := os.Getenv("TLS_VERIFICAR"); val != "" {
+		verificarTLS = strings.ToLower(val) == "true"
+	}
+	if caminho := os.Getenv("TLS_CA_BUNDLE"); caminho != "" {
+		pem, err := os.ReadFile(caminho)
+		if err != nil {
+			return fmt.Errorf("erro ao ler TLS_CA_BUNDLE %s: %w", caminho, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("nenhum certificado válido em %s", caminho)
+		}
+		raizesTLS = pool
+	}
+	return nil
+}
+
+// clientHelloPara retorna o perfil de ClientHello a ser usado para o host.
+func clientHelloPara(host string) utls.ClientHelloID {
+	if perfilTLS != "random" {
+		return perfisTLS[perfilTLS]
+	}
+	escolhido := perfisTLS[perfisAleatorios[rand.Intn(len(perfisAleatorios))]]
+	iface, _ := perfilPorHost.LoadOrStore(host, escolhido)
+	return iface.(utls.ClientHelloID)
+}
+
+// registrarMetadadosTLS salva (uma vez por host) a cadeia de certificados, validade, SANs e
+// protocolo negociado em tls.txt, alertando sobre certificados expirados ou que não batem com o host.
+func registrarMetadadosTLS(host string, estado utls.ConnectionState) {
+	if _, jaRegistrado := tlsRegistrado.LoadOrStore(host, true); jaRegistrado {
+		return
+	}
+	if len(estado.PeerCertificates) == 0 {
+		return
+	}
+	folha := estado.PeerCertificates[0]
+
+	var cadeia []string
+	for _, cert := range estado.PeerCertificates {
+		nome := cert.Subject.CommonName
+		if nome == "" {
+			nome = cert.Subject.String()
+		}
+		cadeia = append(cadeia, nome)
+	}
+
+	var problemas []string
+	agora := time.Now()
+	if agora.After(folha.NotAfter) {
+		problemas = append(problemas, "EXPIRADO")
+	} else if agora.Before(folha.NotBefore) {
+		problemas = append(problemas, "AINDA NÃO VÁLIDO")
+	}
+	if err := folha.VerifyHostname(host); err != nil {
+		problemas = append(problemas, "HOST NÃO CONFERE")
+	}
+	intermediarias := x509.NewCertPool()
+	for _, cert := range estado.PeerCertificates[1:] {
+		intermediarias.AddCert(cert)
+	}
+	if _, err := folha.Verify(x509.VerifyOptions{Roots: raizesTLS, Intermediates: intermediarias}); err != nil {
+		problemas = append(problemas, "CADEIA NÃO CONFIÁVEL")
+	}
+
+	alpn := estado.NegotiatedProtocol
+	if alpn == "" {
+		alpn = "http/1.1"
+	}
+	situacao := "OK"
+	if len(problemas) > 0 {
+		situacao = strings.Join(problemas, ",")
+	}
+
+	linha := fmt.Sprintf("%s | %s | %s | %s | validade: %s | SANs: %s | cadeia: %s | emissor: %s",
+		host,
+		situacao,
+		versaoTLS(estado.Version),
+		alpn,
+		folha.NotAfter.Format("2006-01-02"),
+		strings.Join(folha.DNSNames, ","),
+		strings.Join(cadeia, " <- "),
+		folha.Issuer.CommonName,
+	)
+	LogSave(linha, "tls.txt")
+	if len(problemas) > 0 {
+		Warning("Certificado com problema em %s: %s (validade %s)", host, situacao, folha.NotAfter.Format("2006-01-02"))
+	}
+}
+
+// versaoTLS converte o código da versão negociada em texto.
+func versaoTLS(v uint16) string {
+	switch v {
+	case utls.VersionTLS10:
+		return "TLS1.0"
+	case utls.VersionTLS11:
+		return "TLS1.1"
+	case utls.VersionTLS12:
+		return "TLS1.2"
+	case utls.VersionTLS13:
+		return "TLS1.3"
+	}
+	return fmt.Sprintf("0x%04x", v)
+}
@@ -0,0 +1,190 @@
+// internal\wpdetect\achadostokens.go
+package wpdetect
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"Gowpscanner/internal/utils"
+)
+
+const (
+	// linhasContexto é a quantidade de linhas exibidas antes e depois de cada ocorrência.
+	linhasContexto = 2
+	// larguraContexto limita cada linha de contexto (arquivos minificados têm linhas enormes).
+	larguraContexto = 200
+)
+
+// OcorrenciaToken é uma ocorrência de um segredo encontrada por CheckAllTokens.
+type OcorrenciaToken struct {
+	RegraID  string
+	Servico  string
+	Campo    string
+	Valor    string // valor completo (nunca gravado em texto puro com a redação ligada)
+	Exibicao string // valor como aparece na saída (mascarado por padrão)
+	URL      string
+	Camada   string // decodificações que revelaram o valor (vazio = conteúdo original)
+	Offset   int    // posição do valor na camada, em bytes
+	Linha    int    // linha do valor na camada (a partir de 1)
+	// Posição e linha no conteúdo original (para camadas decodificadas, do trecho codificado que
+	// revelou o valor; iguais a Offset e Linha quando não há camada)
+	OffsetOriginal int
+	LinhaOriginal  int
+	Contexto       []string
+}
+
+// achadoToken agrupa as ocorrências de um mesmo segredo (mesma regra e valor) em várias URLs.
+type achadoToken struct {
+	primeira OcorrenciaToken
+	locais   []OcorrenciaToken
+}
+
+var (
+	achadosTokens = make(map[string]*achadoToken)
+	muAchados     sync.Mutex
+)
+
+// contextoOcorrencia retorna as linhas ao redor da ocorrência, numeradas e com os segredos mascarados.
+// As linhas ocupadas pelo valor são marcadas com ">"; o valor pode ocupar várias linhas (ex.: chave
+// privada), então todo trecho de linha dentro de [Offset, Offset+len(Valor)) é mascarado.
+func contextoOcorrencia(content string, o OcorrenciaToken) []string {
+	linhas := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	inicioValor, fimValor := o.Offset, o.Offset+len(o.Valor)
+	ultimaLinha := o.Linha - 1 + strings.Count(o.Valor, "\n")
+	inicio := max(o.Linha-1-linhasContexto, 0)
+	fim := min(ultimaLinha+linhasContexto, len(linhas)-1)
+
+	// Posição (no conteúdo) do começo da primeira linha do contexto
+	pos := 0
+	for i := 0; i < inicio; i++ {
+		pos += len(linhas[i]) + 1
+	}
+
+	var contexto []string
+	for i := inicio; i <= fim; i++ {
+		linha := linhas[i]
+		comecoLinha, fimLinha := pos, pos+len(linha)
+		pos = fimLinha + 1
+
+		marcador, centro := " ", 0
+		// Trecho da linha ocupado pelo valor
+		a, b := max(comecoLinha, inicioValor), min(fimLinha, fimValor)
+		if a < b {
+			marcador, centro = ">", a-comecoLinha
+			mascara := o.Exibicao
+			if a > inicioValor {
+				// Continuação de um valor de várias linhas
+				mascara = "****"
+			}
+			linha = linha[:a-comecoLinha] + mascara + linha[b-comecoLinha:]
+		}
+		linha = recortarLinha(strings.TrimRight(linha, "\r"), centro)
+		contexto = append(contexto, fmt.Sprintf("%s %5d: %s", marcador, i+1, utils.Redigir(linha)))
+	}
+	return contexto
+}
+
+// recortarLinha limita a linha a larguraContexto caracteres, centrada na posição indicada.
+func recortarLinha(linha string, posicao int) string {
+	if len(linha) <= larguraContexto {
+		return linha
+	}
+	inicio := max(posicao-larguraContexto/4, 0)
+	fim := min(inicio+larguraContexto, len(linha))
+	recorte := strings.ToValidUTF8(linha[inicio:fim], "")
+	if inicio > 0 {
+		recorte = "..." + recorte
+	}
+	if fim < len(linha) {
+		recorte += "..."
+	}
+	return recorte
+}
+
+// local descreve onde a ocorrência está: URL, linha no conteúdo original e, se o valor veio de uma
+// camada decodificada, a camada e a linha dentro dela.
+func (o OcorrenciaToken) local() string {
+	if o.Camada != "" {
+		return fmt.Sprintf("%s linha %d [%s, linha %d]", o.URL, o.LinhaOriginal, o.Camada, o.Linha)
+	}
+	return fmt.Sprintf("%s linha %d", o.URL, o.Linha)
+}
+
+// registrarOcorrencia guarda a ocorrência no achado do segredo. A primeira ocorrência é exibida e salva
+// em tokens.txt; as seguintes (outras URLs) só acrescentam locais ao achado consolidado.
+func registrarOcorrencia(o OcorrenciaToken) {
+	chave := o.RegraID + "\x00" + o.Valor
+
+	muAchados.Lock()
+	achado, existe := achadosTokens[chave]
+	if !existe {
+		achado = &achadoToken{primeira: o}
+		achadosTokens[chave] = achado
+	}
+	for _, local := range achado.locais {
+		if local.URL == o.URL && local.Camada == o.Camada && local.Offset == o.Offset {
+			muAchados.Unlock()
+			return
+		}
+	}
+	achado.locais = append(achado.locais, o)
+	muAchados.Unlock()
+
+	if existe {
+		utils.Info("Token %s (%s) já encontrado, novo local: %s", o.Servico, o.Exibicao, o.local())
+		return
+	}
+	registro := fmt.Sprintf("%s|%s|%s|linha %d|regra %s", o.Servico, o.Exibicao, o.URL, o.LinhaOriginal, o.RegraID)
+	if o.Camada != "" {
+		registro += fmt.Sprintf("|camada %s linha %d", o.Camada, o.Linha)
+	}
+	utils.Warning("%s", registro)
+	utils.BeepAlert()
+	utils.LogSave(registro, "tokens.txt")
+}
+
+// SalvarAchadosTokens grava em retornos/tokens-consolidados.txt um achado por segredo, com todos os
+// locais onde ele apareceu e o contexto mascarado de cada um. Deve ser chamada ao final do scan.
+func SalvarAchadosTokens() {
+	muAchados.Lock()
+	defer muAchados.Unlock()
+	if len(achadosTokens) == 0 {
+		return
+	}
+
+	achados := make([]*achadoToken, 0, len(achadosTokens))
+	locais := 0
+	for _, a := range achadosTokens {
+		achados = append(achados, a)
+		locais += len(a.locais)
+	}
+	sort.Slice(achados, func(i, j int) bool {
+		if achados[i].primeira.Servico != achados[j].primeira.Servico {
+			return achados[i].primeira.Servico < achados[j].primeira.Servico
+		}
+		return achados[i].primeira.Exibicao < achados[j].primeira.Exibicao
+	})
+
+	var sb strings.Builder
+	for _, a := range achados {
+		p := a.primeira
+		fmt.Fprintf(&sb, "[%s] %s | %s | %s | %d locais\n", p.RegraID, p.Servico, p.Campo, p.Exibicao, len(a.locais))
+		for _, l := range a.locais {
+			fmt.Fprintf(&sb, "  - %s (offset %d)\n", l.local(), l.OffsetOriginal)
+			for _, c := range l.Contexto {
+				fmt.Fprintf(&sb, "      %s\n", c)
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	caminho := "./retornos/tokens-consolidados.txt"
+	if err := os.WriteFile(caminho, []byte(utils.Redigir(sb.String())), 0600); err != nil {
+		utils.Error("Erro ao salvar %s: %v", caminho, err)
+		return
+	}
+	utils.Info("%d segredos únicos em %d locais (detalhes em retornos/tokens-consolidados.txt)", len(achados), locais)
+}
@@ -0,0 +1,216 @@
+// internal\wpdetect\decodificacao.go
+package wpdetect
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"Gowpscanner/internal/utils"
+)
+
+var (
+	// profundidadeDecodificacao é quantas camadas (base64 dentro de gzip, etc.) são abertas (0 = desativado).
+	profundidadeDecodificacao = 2
+	// limiteDecodificacao é o total de bytes decodificados por conteúdo analisado.
+	limiteDecodificacao int64 = 2 * 1024 * 1024
+
+	// reBase64 captura trechos longos em base64 (padrão ou URL-safe).
+	reBase64 = regexp.MustCompile(`[A-Za-z0-9+/_-]{32,}={0,2}`)
+	// escapesSimples são os escapes de strings JSON e PHP (\n, \", \/ ...) além de \uXXXX.
+	escapesSimples = map[byte]byte{'\\': '\\', 'n': '\n', 'r': '\r', 't': '\t', '/': '/', '"': '"', '\'': '\''}
+)
+
+// camadaDecodificada é um conteúdo a ser analisado e o caminho de decodificações que o produziu
+// (vazio para o conteúdo original, ex.: "base64@120 > arquivo gzip").
+type camadaDecodificada struct {
+	origem   string
+	conteudo string
+	// original converte uma posição da camada na posição do trecho codificado no conteudo original
+	// (para camadas base64 e gzip/zip, o início do trecho que as contém)
+	original func(int) int
+}
+
+// posicaoOriginal retorna a posição no conteúdo original correspondente à posição na camada.
+func (c camadaDecodificada) posicaoOriginal(offset int) int {
+	if c.original == nil {
+		return offset
+	}
+	return c.original(offset)
+}
+
+// ConfigurarDecodificacao define a profundidade máxima (0 desativa) e o limite de bytes decodificados.
+func ConfigurarDecodificacao(profundidade int, limite int64) {
+	profundidadeDecodificacao = profundidade
+	if limite > 0 {
+		limiteDecodificacao = limite
+	}
+}
+
+// camadasDecodificadas retorna o conteúdo original seguido das camadas obtidas decodificando trechos
+// base64, desescapando strings JSON/PHP e descompactando gzip/zip, recursivamente até a profundidade
+// e o limite de bytes configurados. Camadas repetidas são descartadas.
+func camadasDecodificadas(content string) []camadaDecodificada {
+	restante := limiteDecodificacao
+	// Um conteúdo compactado não é analisado em binário: as camadas iniciais são os arquivos dele
+	camadas := expandirCompactado(camadaDecodificada{conteudo: content}, restante)
+	vistos := make(map[[32]byte]bool)
+	for _, c := range camadas {
+		vistos[sha256.Sum256([]byte(c.conteudo))] = true
+	}
+
+	nivel := camadas
+	for profundidade := 0; profundidade < profundidadeDecodificacao && len(nivel) > 0; profundidade++ {
+		var proximo []camadaDecodificada
+		for _, c := range nivel {
+			for _, filha := range decodificarCamada(c, restante) {
+				soma := sha256.Sum256([]byte(filha.conteudo))
+				if vistos[soma] || int64(len(filha.conteudo)) > restante {
+					continue
+				}
+				vistos[soma] = true
+				restante -= int64(len(filha.conteudo))
+				if c.origem != "" {
+					filha.origem = c.origem + " > " + filha.origem
+				}
+				proximo = append(proximo, filha)
+			}
+		}
+		camadas = append(camadas, proximo...)
+		nivel = proximo
+	}
+	return camadas
+}
+
+// expandirCompactado troca uma camada gzip/zip pelos arquivos de texto contidos nela.
+// Camadas que não são compactadas são retornadas como estão.
+func expandirCompactado(c camadaDecodificada, limite int64) []camadaDecodificada {
+	arquivos, err := utils.Descompactar([]byte(c.conteudo), limite)
+	if err != nil {
+		return []camadaDecodificada{c}
+	}
+	var camadas []camadaDecodificada
+	for _, a := range arquivos {
+		if utils.TipoBinario(utils.DetectarTipo(a.Conteudo[:min(len(a.Conteudo), 512)])) {
+			continue
+		}
+		origem := "arquivo " + a.Nome
+		if c.origem != "" {
+			origem = c.origem + " > " + origem
+		}
+		inicio := c.posicaoOriginal(0)
+		camadas = append(camadas, camadaDecodificada{
+			origem:   origem,
+			conteudo: string(a.Conteudo),
+			original: func(int) int { return inicio },
+		})
+	}
+	return camadas
+}
+
+// decodificarCamada abre uma única camada do conteúdo.
+func decodificarCamada(c camadaDecodificada, limite int64) []camadaDecodificada {
+	var filhas []camadaDecodificada
+	content := c.conteudo
+
+	// Strings JSON/PHP com escapes (ex.: private_key da conta de serviço do GCP com \n)
+	if strings.Contains(content, `\`) {
+		if desescapado, mapa := desescaparComMapa(content); desescapado != content {
+			filhas = append(filhas, camadaDecodificada{
+				origem:   "desescape",
+				conteudo: desescapado,
+				original: func(offset int) int { return c.posicaoOriginal(mapa[min(offset, len(mapa)-1)]) },
+			})
+		}
+	}
+
+	// Trechos em base64 que decodificam para texto ou para um arquivo compactado
+	for _, idx := range reBase64.FindAllStringIndex(content, -1) {
+		decodificado, ok := decodificarBase64(content[idx[0]:idx[1]])
+		if !ok {
+			continue
+		}
+		inicio := c.posicaoOriginal(idx[0])
+		filha := camadaDecodificada{
+			origem:   fmt.Sprintf("base64@%d", idx[0]),
+			conteudo: decodificado,
+			original: func(int) int { return inicio },
+		}
+		filhas = append(filhas, expandirCompactado(filha, limite)...)
+	}
+	return filhas
+}
+
+// desescapar trata os escapes de strings JSON/PHP, inclusive \uXXXX.
+func desescapar(content string) string {
+	desescapado, _ := desescaparComMapa(content)
+	return desescapado
+}
+
+// desescaparComMapa desescapa o conteúdo e retorna, para cada byte do resultado (e para o fim dele),
+// a posição correspondente no conteúdo escapado.
+func desescaparComMapa(content string) (string, []int) {
+	var sb strings.Builder
+	mapa := make([]int, 0, len(content)+1)
+	for i := 0; i < len(content); {
+		if content[i] == '\\' && i+1 < len(content) {
+			if content[i+1] == 'u' && i+6 <= len(content) {
+				if r, err := strconv.ParseUint(content[i+2:i+6], 16, 32); err == nil {
+					n, _ := sb.WriteRune(rune(r))
+					for j := 0; j < n; j++ {
+						mapa = append(mapa, i)
+					}
+					i += 6
+					continue
+				}
+			}
+			if e, ok := escapesSimples[content[i+1]]; ok {
+				sb.WriteByte(e)
+				mapa = append(mapa, i)
+				i += 2
+				continue
+			}
+		}
+		sb.WriteByte(content[i])
+		mapa = append(mapa, i)
+		i++
+	}
+	return sb.String(), append(mapa, len(content))
+}
+
+// decodificarBase64 tenta as variantes padrão e URL-safe (com e sem padding) e só aceita o resultado
+// se for texto legível ou gzip/zip.
+func decodificarBase64(trecho string) (string, bool) {
+	semPadding := strings.TrimRight(trecho, "=")
+	for _, enc := range []*base64.Encoding{base64.RawStdEncoding, base64.RawURLEncoding} {
+		dados, err := enc.DecodeString(semPadding)
+		if err != nil || len(dados) == 0 {
+			continue
+		}
+		tipo := utils.DetectarTipo(dados[:min(len(dados), 512)])
+		if tipo == "gzip" || tipo == "zip" || textoLegivel(dados) {
+			return string(dados), true
+		}
+	}
+	return "", false
+}
+
+// textoLegivel indica se os bytes são UTF-8 válido com no mínimo 95% de caracteres imprimíveis.
+func textoLegivel(dados []byte) bool {
+	if !utf8.Valid(dados) {
+		return false
+	}
+	total, imprimiveis := 0, 0
+	for _, r := range string(dados) {
+		total++
+		if unicode.IsPrint(r) || unicode.IsSpace(r) {
+			imprimiveis++
+		}
+	}
+	return total > 0 && imprimiveis*100 >= total*95
+}
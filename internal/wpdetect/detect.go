@@ -61,9 +61,7 @@ func IsWordPress(baseURL string) (bool, string) {
 		CheckFirebaseIO(body)
 		CheckDigitalOceanToken(body)
 		// Checa sinais de WordPress
-		if strings.Contains(body, "wp-content") ||
-			strings.Contains(body, "wp-includes") ||
-			strings.Contains(body, `generator" content="WordPress`) {
+		if TemSinaisWordPress(body) {
 			// Extrai a versão a partir do meta tag generator.
 			// Essa expressão regular procura por:
 			//   <meta name="generator" content="WordPress 6.7.1">
@@ -86,3 +84,10 @@ func IsWordPress(baseURL string) (bool, string) {
 	}
 	return false, ""
 }
+
+// TemSinaisWordPress indica se a página tem os sinais de uma instalação WordPress.
+func TemSinaisWordPress(body string) bool {
+	return strings.Contains(body, "wp-content") ||
+		strings.Contains(body, "wp-includes") ||
+		strings.Contains(body, `generator" content="WordPress`)
+}
@@ -39,26 +39,39 @@ func CheckDigitalOceanToken(content string) {
 
 	// Itera sobre cada token encontrado e os salva
 	for token := range matchesMap {
+		exibicao := utils.RegistrarSegredo(token, "DigitalOcean")
+		// No modo offline o token não é validado na API da DigitalOcean
+		if modoOffline {
+			utils.LogSave(exibicao+" - não verificado", "digitalocean_tokens_nao_verificados.txt")
+			utils.Warning("Token DigitalOcean exposto encontrado (não verificado, modo offline): %s", exibicao)
+			utils.BeepAlert()
+			continue
+		}
 		test := TestDigitalOceanToken(token)
 		if !test {
-			utils.LogSave(token, "digitalocean_tokens_die.txt")
+			utils.LogSave(exibicao, "digitalocean_tokens_die.txt")
 		} else {
 
-			utils.LogSave(token, "digitalocean_tokens_live.txt")
+			utils.LogSave(exibicao, "digitalocean_tokens_live.txt")
 		}
-		utils.Warning("Token DigitalOcean exposto encontrado: %s", token)
+		utils.Warning("Token DigitalOcean exposto encontrado: %s", exibicao)
 		utils.BeepAlert()
 	}
 }
 
 // TestDigitalOceanToken verifica se um token DigitalOcean é válido.
+// No modo offline não faz a chamada e retorna false.
 func TestDigitalOceanToken(token string) bool {
+	if modoOffline {
+		return false
+	}
 	// Endpoint da API DigitalOcean para validação de token
 	apiURL := "https://api.digitalocean.com/v2/account"
 
 	// Criando um client HTTP com timeout
 	client := &http.Client{
-		Timeout: 5 * time.Second,
+		Timeout:   5 * time.Second,
+		Transport: utils.EnvolverTransporte(http.DefaultTransport),
 	}
 
 	// Criando a requisição GET com o token no cabeçalho de autorização
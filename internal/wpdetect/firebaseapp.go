@@ -44,6 +44,12 @@ func CheckFirebaseIO(content string) {
 	// Itera sobre cada link encontrado e testa a vulnerabilidade
 
 	for link := range matchesMap {
+		// No modo offline o banco não é testado: o link é registrado como não verificado
+		if modoOffline {
+			utils.LogSave("https://"+link+"/.json - NaoVerificado", "firebaseio.txt")
+			utils.Info("Link Firebase encontrado (não verificado, modo offline): %s", link)
+			continue
+		}
 		if TestInsecureFirebase(link) {
 			// Salva os links vulneráveis no arquivo firebaseio.txt
 			utils.LogSave("https://"+link+"/.json - InsecureFirebase", "firebaseio.txt")
@@ -62,7 +68,11 @@ func CheckFirebaseIO(content string) {
 // TestInsecureFirebase testa se o host Firebase (por exemplo, "example.firebaseio.com")
 // está vulnerável (i.e. com regras inseguras que permitem PUT e GET sem restrição)
 // conforme a definição do teste "insecure-firebase-database".
+// No modo offline não faz a chamada e retorna false.
 func TestInsecureFirebase(host string) bool {
+	if modoOffline {
+		return false
+	}
 	// Garante que o host possua protocolo HTTPS.
 	urlBase := "https://" + host
 
@@ -77,9 +87,9 @@ func TestInsecureFirebase(host string) bool {
 	// Cria um client HTTP com timeout e configuração para ignorar verificação TLS.
 	client := &http.Client{
 		Timeout: 5 * time.Second,
-		Transport: &http.Transport{
+		Transport: utils.EnvolverTransporte(&http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
+		}),
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
@@ -155,16 +165,20 @@ func RandString(n int) string {
 
 // TestFirebaseOpenRead testa se o host Firebase possui leitura aberta
 // ao acessar "https://{host}/.json". Retorna true se o status code for 200.
+// No modo offline não faz a chamada e retorna false.
 func TestFirebaseOpenRead(host string) bool {
+	if modoOffline {
+		return false
+	}
 	// Constrói a URL de teste.
 	testURL := "https://" + host + "/.json"
 
 	// Cria um client HTTP com timeout e configuração para ignorar verificação TLS.
 	client := &http.Client{
 		Timeout: 5 * time.Second,
-		Transport: &http.Transport{
+		Transport: utils.EnvolverTransporte(&http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
+		}),
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
@@ -0,0 +1,94 @@
+// internal\wpdetect\gitleaks.go
+package wpdetect
+
+import (
+	"github.com/BurntSushi/toml"
+
+	"Gowpscanner/internal/utils"
+)
+
+// configGitleaks é o subconjunto do formato de configuração do gitleaks (v8) usado pelo scanner.
+type configGitleaks struct {
+	Title      string              `toml:"title"`
+	Allowlist  *allowlistGitleaks  `toml:"allowlist"`
+	Allowlists []allowlistGitleaks `toml:"allowlists"`
+	Rules      []regraGitleaks     `toml:"rules"`
+}
+
+type regraGitleaks struct {
+	ID          string              `toml:"id"`
+	Description string              `toml:"description"`
+	Regex       string              `toml:"regex"`
+	Path        string              `toml:"path"`
+	SecretGroup int                 `toml:"secretGroup"`
+	Entropy     float64             `toml:"entropy"`
+	Keywords    []string            `toml:"keywords"`
+	Allowlist   *allowlistGitleaks  `toml:"allowlist"`
+	Allowlists  []allowlistGitleaks `toml:"allowlists"`
+}
+
+type allowlistGitleaks struct {
+	Condition   string   `toml:"condition"`
+	RegexTarget string   `toml:"regexTarget"`
+	Regexes     []string `toml:"regexes"`
+	Paths       []string `toml:"paths"`
+	StopWords   []string `toml:"stopwords"`
+}
+
+// converterGitleaks lê uma configuração do gitleaks e a converte para o formato de regras do scanner.
+// O serviço de cada regra é o id do gitleaks; secretGroup 0 usa o primeiro grupo não vazio, como no gitleaks.
+// Regras só de caminho (sem regex) não se aplicam a conteúdo e são ignoradas.
+func converterGitleaks(data []byte) (arquivoRegrasTokens, error) {
+	var cfg configGitleaks
+	var arquivo arquivoRegrasTokens
+	if _, err := toml.Decode(string(data), &cfg); err != nil {
+		return arquivo, err
+	}
+
+	arquivo.Allowlists = converterAllowlistsGitleaks(cfg.Allowlist, cfg.Allowlists)
+	ignoradas := 0
+	for _, r := range cfg.Rules {
+		if r.Regex == "" {
+			ignoradas++
+			continue
+		}
+		regra := regraToken{
+			ID:         r.ID,
+			Servico:    r.ID,
+			Campo:      "secret",
+			Tipo:       "concealed",
+			Regex:      r.Regex,
+			Palavras:   r.Keywords,
+			Entropia:   r.Entropy,
+			Caminho:    r.Path,
+			Allowlists: converterAllowlistsGitleaks(r.Allowlist, r.Allowlists),
+		}
+		if r.SecretGroup > 0 {
+			grupo := r.SecretGroup
+			regra.Grupo = &grupo
+		}
+		arquivo.Regras = append(arquivo.Regras, regra)
+	}
+	if ignoradas > 0 {
+		utils.Info("Gitleaks: %d regras sem regex (apenas caminho) ignoradas", ignoradas)
+	}
+	return arquivo, nil
+}
+
+// converterAllowlistsGitleaks junta a allowlist única (formato antigo) e a lista de allowlists (formato novo).
+func converterAllowlistsGitleaks(unica *allowlistGitleaks, lista []allowlistGitleaks) []allowlistRegra {
+	if unica != nil {
+		lista = append([]allowlistGitleaks{*unica}, lista...)
+	}
+	var convertidas []allowlistRegra
+	for _, a := range lista {
+		convertidas = append(convertidas, allowlistRegra{
+			Condicao:  a.Condition,
+			Alvo:      a.RegexTarget,
+			Regexes:   a.Regexes,
+			Caminhos:  a.Paths,
+			Stopwords: a.StopWords,
+		})
+	}
+	return convertidas
+}
@@ -0,0 +1,97 @@
+// internal\wpdetect\indicegit.go
+package wpdetect
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// ArquivosIndiceGit lê os nomes dos arquivos do .git/index (versões 2, 3 e 4).
+// Um índice truncado devolve os nomes lidos até o ponto do corte, junto com o erro.
+func ArquivosIndiceGit(dados []byte) ([]string, error) {
+	if len(dados) < 12 || !bytes.HasPrefix(dados, []byte("DIRC")) {
+		return nil, fmt.Errorf("assinatura DIRC ausente")
+	}
+	versao := binary.BigEndian.Uint32(dados[4:8])
+	total := binary.BigEndian.Uint32(dados[8:12])
+	if versao < 2 || versao > 4 {
+		return nil, fmt.Errorf("versão de índice não suportada: %d", versao)
+	}
+
+	var nomes []string
+	var anterior string
+	pos := 12
+	for i := uint32(0); i < total; i++ {
+		inicio := pos
+		// ctime, mtime, dev, ino, mode, uid, gid, size (40 bytes) + sha1 (20) + flags (2)
+		if pos+62 > len(dados) {
+			return nomes, fmt.Errorf("índice truncado na entrada %d", i)
+		}
+		flags := binary.BigEndian.Uint16(dados[pos+60 : pos+62])
+		pos += 62
+		if versao >= 3 && flags&0x4000 != 0 {
+			pos += 2 // flags estendidas
+		}
+		if pos >= len(dados) {
+			return nomes, fmt.Errorf("índice truncado na entrada %d", i)
+		}
+
+		var nome string
+		if versao == 4 {
+			// Nome comprimido: quantos bytes remover do nome anterior + sufixo terminado em NUL
+			remover, n := varintGit(dados[pos:])
+			if n == 0 || remover < 0 || remover > len(anterior) {
+				return nomes, fmt.Errorf("prefixo inválido na entrada %d", i)
+			}
+			pos += n
+			fim := bytes.IndexByte(dados[pos:], 0)
+			if fim == -1 {
+				return nomes, fmt.Errorf("índice truncado na entrada %d", i)
+			}
+			nome = anterior[:len(anterior)-remover] + string(dados[pos:pos+fim])
+			pos += fim + 1
+		} else {
+			fim := bytes.IndexByte(dados[pos:], 0)
+			if fim == -1 {
+				return nomes, fmt.Errorf("índice truncado na entrada %d", i)
+			}
+			nome = string(dados[pos : pos+fim])
+			// Entradas das versões 2 e 3 são completadas com NUL até múltiplo de 8 bytes
+			tamanho := (pos + fim - inicio + 8) &^ 7
+			pos = inicio + tamanho
+		}
+		nomes = append(nomes, nome)
+		anterior = nome
+	}
+	return nomes, nil
+}
+
+// maxBytesVarintGit é o maior varint aceito: 9 bytes (63 bits) já cobrem qualquer int positivo.
+const maxBytesVarintGit = 9
+
+// varintGit decodifica o inteiro de tamanho variável usado no índice v4 do git.
+// Retorna o valor e a quantidade de bytes lidos (0 se inválido, longo demais ou se estourar o int,
+// como no decode_varint do git: um índice malicioso não pode gerar um valor negativo).
+func varintGit(dados []byte) (int, int) {
+	if len(dados) == 0 {
+		return 0, 0
+	}
+	c := dados[0]
+	valor := int(c & 127)
+	n := 1
+	for c&128 != 0 {
+		if n >= len(dados) || n >= maxBytesVarintGit {
+			return 0, 0
+		}
+		valor++
+		if valor > math.MaxInt>>7 {
+			return 0, 0
+		}
+		c = dados[n]
+		n++
+		valor = (valor << 7) + int(c&127)
+	}
+	return valor, n
+}
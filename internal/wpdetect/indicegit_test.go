@@ -0,0 +1,129 @@
+package wpdetect
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// varintGitCodificar codifica como o encode_varint do git (deslocamento do índice v4).
+func varintGitCodificar(valor int) []byte {
+	saida := []byte{byte(valor & 127)}
+	for valor >>= 7; valor != 0; valor >>= 7 {
+		valor--
+		saida = append([]byte{byte(128 | valor&127)}, saida...)
+	}
+	return saida
+}
+
+// montarIndiceGit gera um .git/index com as entradas informadas: nas versões 2 e 3 o nome é completado
+// com NUL até múltiplo de 8 bytes; na versão 4 o nome é comprimido em relação ao anterior.
+func montarIndiceGit(versao uint32, nomes []string) []byte {
+	dados := []byte("DIRC")
+	dados = binary.BigEndian.AppendUint32(dados, versao)
+	dados = binary.BigEndian.AppendUint32(dados, uint32(len(nomes)))
+	anterior := ""
+	for _, nome := range nomes {
+		entrada := make([]byte, 60) // stat (40 bytes) + sha1 (20)
+		entrada = binary.BigEndian.AppendUint16(entrada, uint16(min(len(nome), 0xfff)))
+		if versao == 4 {
+			comum := 0
+			for comum < len(nome) && comum < len(anterior) && nome[comum] == anterior[comum] {
+				comum++
+			}
+			entrada = append(entrada, varintGitCodificar(len(anterior)-comum)...)
+			entrada = append(entrada, nome[comum:]...)
+			entrada = append(entrada, 0)
+		} else {
+			entrada = append(entrada, nome...)
+			entrada = append(entrada, make([]byte, 8-(len(entrada)%8))...)
+		}
+		dados = append(dados, entrada...)
+		anterior = nome
+	}
+	// Extensões e checksum vêm depois das entradas e não são lidos
+	return append(dados, make([]byte, 20)...)
+}
+
+func TestArquivosIndiceGit(t *testing.T) {
+	nomes := []string{
+		".env",
+		"wp-config.php",
+		"wp-content/plugins/akismet/akismet.php",
+		"wp-content/plugins/akismet/readme.txt",
+		"wp-content/themes/twentytwenty/functions.php",
+		"x",
+		"xyz1234",
+	}
+	// Prefixo removido maior que 127 (varint de dois bytes)
+	longo := "dir/" + strings.Repeat("a", 200) + "/arquivo.php"
+	nomesLongos := []string{longo, "outro.php"}
+
+	casos := []struct {
+		nome      string
+		dados     []byte
+		esperados []string
+		erro      bool
+	}{
+		{nome: "v2 com preenchimento", dados: montarIndiceGit(2, nomes), esperados: nomes},
+		{nome: "v3", dados: montarIndiceGit(3, nomes), esperados: nomes},
+		{nome: "v4 com prefixo comprimido", dados: montarIndiceGit(4, nomes), esperados: nomes},
+		{nome: "v4 com varint de dois bytes", dados: montarIndiceGit(4, nomesLongos), esperados: nomesLongos},
+		{nome: "v2 truncado no meio da terceira entrada", dados: montarIndiceGit(2, nomes)[:12+24*8+10], esperados: nomes[:2], erro: true},
+		{nome: "v4 truncado no nome", dados: montarIndiceGit(4, nomes)[:12+63+4+63+12], esperados: nomes[:1], erro: true},
+		{nome: "sem assinatura", dados: []byte("<html>nada</html>"), erro: true},
+		{nome: "versão desconhecida", dados: montarIndiceGit(5, nomes), erro: true},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			arquivos, err := ArquivosIndiceGit(c.dados)
+			if (err != nil) != c.erro {
+				t.Fatalf("erro = %v, esperado erro: %v", err, c.erro)
+			}
+			if !reflect.DeepEqual(arquivos, c.esperados) {
+				t.Errorf("arquivos %q, esperado %q", arquivos, c.esperados)
+			}
+		})
+	}
+}
+
+func TestVarintGit(t *testing.T) {
+	for _, valor := range []int{0, 1, 127, 128, 200, 16511, 16512, 1 << 20} {
+		codificado := varintGitCodificar(valor)
+		lido, n := varintGit(codificado)
+		if lido != valor || n != len(codificado) {
+			t.Errorf("varintGit(% x) = %d, %d; esperado %d, %d", codificado, lido, n, valor, len(codificado))
+		}
+	}
+	invalidos := map[string][]byte{
+		"continuação sem o byte seguinte": {0x80},
+		"mais de 9 bytes":                 append(bytes.Repeat([]byte{0x80}, 9), 0x00),
+		"estouro do int":                  append(bytes.Repeat([]byte{0xff}, 8), 0x7f),
+	}
+	for nome, dados := range invalidos {
+		if valor, n := varintGit(dados); n != 0 {
+			t.Errorf("%s: varint aceito (valor %d, n %d)", nome, valor, n)
+		}
+	}
+}
+
+// Um deslocamento v4 malicioso (varint que estoura o int) não pode derrubar o scanner.
+func TestArquivosIndiceGitV4Malicioso(t *testing.T) {
+	dados := montarIndiceGit(4, []string{"a"})
+	dados = dados[:len(dados)-20]
+	dados[11] = 2 // segunda entrada
+	entrada := make([]byte, 62)
+	entrada = append(entrada, bytes.Repeat([]byte{0xff}, 8)...)
+	entrada = append(entrada, 0x7f, 'x', 0)
+	dados = append(dados, entrada...)
+
+	arquivos, err := ArquivosIndiceGit(dados)
+	if err == nil {
+		t.Fatal("índice com deslocamento inválido aceito")
+	}
+	if !reflect.DeepEqual(arquivos, []string{"a"}) {
+		t.Errorf("arquivos %q, esperado [a]", arquivos)
+	}
+}
@@ -0,0 +1,17 @@
+// internal\wpdetect\offline.go
+package wpdetect
+
+// modoOffline impede qualquer chamada a serviços de terceiros (API da DigitalOcean, bancos *.firebaseio.com).
+// Ligado por padrão: o scan só acessa os alvos e os achados que dependeriam dessas chamadas são
+// registrados como "não verificado".
+var modoOffline = true
+
+// ConfigurarModoOffline liga ou desliga as verificações que contatam serviços de terceiros.
+func ConfigurarModoOffline(ativo bool) {
+	modoOffline = ativo
+}
+
+// ModoOffline indica se as chamadas a serviços de terceiros estão desativadas.
+func ModoOffline() bool {
+	return modoOffline
+}
@@ -0,0 +1,164 @@
+// internal\wpdetect\segredosgenericos.go
+package wpdetect
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"Gowpscanner/internal/utils"
+)
+
+// Limites do detector genérico (ver ConfigurarSegredosGenericos).
+var (
+	entropiaMinima      = 3.5
+	entropiaMinimaSenha = 2.5
+	tamanhoMinimoValor  = 6
+	placeholdersExatos  = map[string]bool{}
+	placeholdersRegex   []*regexp.Regexp
+)
+
+var (
+	// reChaveSensivel reconhece nomes de chaves que costumam guardar segredos. "auth" e "token" só casam
+	// no fim da chave (auth, access_token, x-auth-token), não em auth_uri ou token_uri, que guardam URLs.
+	reChaveSensivel = regexp.MustCompile(`(?i)(pass(word|wd)?|pwd|senha|secret|(auth|token)s?$|api[_.-]?key|apikey|access[_.-]?key|private[_.-]?key|client[_.-]?secret|credential|salt|signing|encrypt|(^|[_.-])key$)`)
+	// reChaveSenha reconhece as chaves de senha, que usam um limite de entropia menor.
+	reChaveSenha = regexp.MustCompile(`(?i)(pass(word|wd)?|pwd|senha)`)
+
+	// Extratores de pares chave/valor de cada formato
+	reParEnv    = regexp.MustCompile(`(?m)^[ \t]*(?:export[ \t]+)?([A-Za-z_][\w.-]*)[ \t]*=[ \t]*("[^"\n]*"|'[^'\n]*'|[^\s#]+)`)
+	reParYaml   = regexp.MustCompile(`(?m)^[ \t]*-?[ \t]*["']?([\w.-]+)["']?[ \t]*:[ \t]+("[^"\n]*"|'[^'\n]*'|[^\s#][^\n#]*?)[ \t]*(?:#.*)?$`)
+	reParDefine = regexp.MustCompile(`define\(\s*['"]([^'"]+)['"]\s*,\s*(?:'([^']*)'|"([^"]*)")\s*\)`)
+	reParPHP    = regexp.MustCompile(`['"]([\w.-]+)['"]\s*=>\s*(?:'([^']*)'|"([^"]*)")|\$([\w]+)\s*=\s*(?:'([^']*)'|"([^"]*)")\s*;`)
+	// Atribuição a um índice de array: $config['encryption_key'] = '...'; (a chave é o último índice)
+	reParPHPIndice = regexp.MustCompile(`\$\w+(?:\[\s*['"][\w.-]*['"]\s*\])*\[\s*['"]([\w.-]+)['"]\s*\]\s*=\s*(?:'([^']*)'|"([^"]*)")\s*;`)
+	reParJSON      = regexp.MustCompile(`"([^"\\]+)"\s*:\s*"((?:[^"\\]|\\.)*)"`)
+
+	// reURLSemCredenciais reconhece URLs sem usuário/senha (ex.: token_uri do service account do GCP)
+	reURLSemCredenciais = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*://[^/@\s]*(?:[/?#]\S*)?$`)
+)
+
+// ConfigurarSegredosGenericos define os limites de entropia (geral e para senhas), o tamanho mínimo
+// dos valores e a lista de placeholders ignorados. Entradas com prefixo "re:" são expressões regulares.
+func ConfigurarSegredosGenericos(entropia, entropiaSenha float64, tamanhoMinimo int, placeholders []string) error {
+	entropiaMinima = entropia
+	entropiaMinimaSenha = entropiaSenha
+	tamanhoMinimoValor = tamanhoMinimo
+	exatos := make(map[string]bool)
+	var regexes []*regexp.Regexp
+	for _, p := range placeholders {
+		if expr, ok := strings.CutPrefix(p, "re:"); ok {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return fmt.Errorf("placeholder inválido %q: %w", p, err)
+			}
+			regexes = append(regexes, re)
+			continue
+		}
+		exatos[strings.ToLower(p)] = true
+	}
+	placeholdersExatos = exatos
+	placeholdersRegex = regexes
+	return nil
+}
+
+// parChaveValor é um par extraído de um .env, YAML, define() do PHP ou JSON.
+type parChaveValor struct {
+	chave string
+	valor string
+}
+
+// CheckSegredosGenericos procura segredos sem formato conhecido: extrai os pares chave/valor do conteúdo
+// (.env, YAML, define()/arrays do PHP e JSON), inclusive das camadas decodificadas (base64, strings
+// escapadas, gzip/zip), e reporta os valores de alta entropia em chaves sensíveis.
+func CheckSegredosGenericos(content string, url string) {
+	vistos := make(map[string]bool)
+	for _, camada := range camadasDecodificadas(content) {
+		checkSegredosCamada(camada, url, vistos)
+	}
+}
+
+// checkSegredosCamada procura os segredos genéricos de uma camada do conteúdo.
+func checkSegredosCamada(camada camadaDecodificada, url string, vistos map[string]bool) {
+	local := url
+	if camada.origem != "" {
+		local += " [" + camada.origem + "]"
+	}
+	for _, par := range extrairParesChaveValor(camada.conteudo) {
+		if !reChaveSensivel.MatchString(par.chave) {
+			continue
+		}
+		valor := limparValor(par.valor)
+		if len(valor) < tamanhoMinimoValor || ehPlaceholder(valor) || reURLSemCredenciais.MatchString(valor) {
+			continue
+		}
+		minimo := entropiaMinima
+		if reChaveSenha.MatchString(par.chave) {
+			minimo = entropiaMinimaSenha
+		}
+		entropia := entropiaShannon(valor)
+		if entropia < minimo {
+			continue
+		}
+		if vistos[par.chave+"\x00"+valor] {
+			continue
+		}
+		vistos[par.chave+"\x00"+valor] = true
+
+		registro := fmt.Sprintf("%s|%s|%s|entropia %.2f", par.chave, utils.RegistrarSegredo(valor, par.chave+" - "+url), local, entropia)
+		utils.Warning("Segredo genérico: %s", registro)
+		utils.BeepAlert()
+		utils.LogSave(registro, "segredos-genericos.txt")
+	}
+}
+
+// extrairParesChaveValor aplica os extratores de todos os formatos; o formato do arquivo nem sempre
+// corresponde à extensão (ex.: backups do wp-config com extensões diversas).
+func extrairParesChaveValor(content string) []parChaveValor {
+	var pares []parChaveValor
+	for _, m := range reParEnv.FindAllStringSubmatch(content, -1) {
+		pares = append(pares, parChaveValor{m[1], m[2]})
+	}
+	for _, m := range reParYaml.FindAllStringSubmatch(content, -1) {
+		pares = append(pares, parChaveValor{m[1], m[2]})
+	}
+	for _, m := range reParDefine.FindAllStringSubmatch(content, -1) {
+		pares = append(pares, parChaveValor{m[1], m[2] + m[3]})
+	}
+	for _, m := range reParPHP.FindAllStringSubmatch(content, -1) {
+		if m[1] != "" {
+			pares = append(pares, parChaveValor{m[1], m[2] + m[3]})
+		} else {
+			pares = append(pares, parChaveValor{m[4], m[5] + m[6]})
+		}
+	}
+	for _, m := range reParPHPIndice.FindAllStringSubmatch(content, -1) {
+		pares = append(pares, parChaveValor{m[1], m[2] + m[3]})
+	}
+	for _, m := range reParJSON.FindAllStringSubmatch(content, -1) {
+		pares = append(pares, parChaveValor{m[1], m[2]})
+	}
+	return pares
+}
+
+// limparValor remove espaços, aspas e a vírgula final do valor.
+func limparValor(valor string) string {
+	valor = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(valor), ","))
+	if len(valor) >= 2 && (valor[0] == '"' || valor[0] == '\'') && valor[len(valor)-1] == valor[0] {
+		valor = valor[1 : len(valor)-1]
+	}
+	return valor
+}
+
+// ehPlaceholder indica se o valor é um exemplo/placeholder conhecido.
+func ehPlaceholder(valor string) bool {
+	if placeholdersExatos[strings.ToLower(valor)] {
+		return true
+	}
+	for _, re := range placeholdersRegex {
+		if re.MatchString(valor) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,307 @@
+// internal\wpdetect\yamlsensivel.go
+package wpdetect
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// assinaturaYaml identifica um tipo de arquivo YAML, como escrita em paths/yaml_assinaturas.yml.
+type assinaturaYaml struct {
+	Nome     string   `yaml:"nome"`
+	Requer   []string `yaml:"requer"`
+	Algum    []string `yaml:"algum"`
+	Conteudo []string `yaml:"conteudo"`
+}
+
+// arquivoAssinaturasYaml é o formato do arquivo de assinaturas YAML.
+type arquivoAssinaturasYaml struct {
+	ChavesSensiveis  []string         `yaml:"chaves_sensiveis"`
+	ValoresSensiveis []string         `yaml:"valores_sensiveis"`
+	ValoresIgnorados []string         `yaml:"valores_ignorados"`
+	Assinaturas      []assinaturaYaml `yaml:"assinaturas"`
+}
+
+// assinaturaCompilada é uma assinatura com os caminhos já divididos em chaves.
+type assinaturaCompilada struct {
+	nome     string
+	requer   [][]string
+	algum    [][]string
+	conteudo []string
+}
+
+// SegredoYaml é um valor sensível encontrado no YAML e o caminho das chaves até ele (ex.: production.password).
+type SegredoYaml struct {
+	Caminho string
+	Valor   string
+}
+
+// AnaliseYaml é o resultado de AnalisarYaml: os tipos de arquivo reconhecidos e os segredos encontrados.
+type AnaliseYaml struct {
+	Tipos    []string
+	Segredos []SegredoYaml
+}
+
+// filhoYaml é uma chave de mapa (ou item de lista, com chave vazia) e o seu valor.
+type filhoYaml struct {
+	chave string
+	valor interface{}
+}
+
+var (
+	chavesSensiveisYaml  []*regexp.Regexp
+	valoresSensiveisYaml []*regexp.Regexp
+	valoresIgnoradosYaml []*regexp.Regexp
+	assinaturasYaml      []assinaturaCompilada
+)
+
+// CarregarAssinaturasYaml lê as chaves sensíveis e as assinaturas de tipos de arquivo usadas por AnalisarYaml.
+func CarregarAssinaturasYaml(caminho string) error {
+	data, err := os.ReadFile(caminho)
+	if err != nil {
+		return fmt.Errorf("erro ao ler assinaturas YAML %s: %w", caminho, err)
+	}
+	var arquivo arquivoAssinaturasYaml
+	if err := yaml.Unmarshal(data, &arquivo); err != nil {
+		return fmt.Errorf("erro ao interpretar assinaturas YAML %s: %w", caminho, err)
+	}
+
+	chaves, err := compilarRegexes(arquivo.ChavesSensiveis)
+	if err != nil {
+		return fmt.Errorf("%s: chaves_sensiveis: %w", caminho, err)
+	}
+	valores, err := compilarRegexes(arquivo.ValoresSensiveis)
+	if err != nil {
+		return fmt.Errorf("%s: valores_sensiveis: %w", caminho, err)
+	}
+	ignorados, err := compilarRegexes(arquivo.ValoresIgnorados)
+	if err != nil {
+		return fmt.Errorf("%s: valores_ignorados: %w", caminho, err)
+	}
+
+	var assinaturas []assinaturaCompilada
+	for _, a := range arquivo.Assinaturas {
+		if a.Nome == "" || len(a.Requer)+len(a.Algum)+len(a.Conteudo) == 0 {
+			return fmt.Errorf("%s: assinatura incompleta (nome e requer, algum ou conteudo são obrigatórios): %+v", caminho, a)
+		}
+		ac := assinaturaCompilada{nome: a.Nome, requer: dividirCaminhos(a.Requer), algum: dividirCaminhos(a.Algum)}
+		for _, c := range a.Conteudo {
+			ac.conteudo = append(ac.conteudo, strings.ToLower(c))
+		}
+		assinaturas = append(assinaturas, ac)
+	}
+
+	chavesSensiveisYaml, valoresSensiveisYaml, valoresIgnoradosYaml = chaves, valores, ignorados
+	assinaturasYaml = assinaturas
+	return nil
+}
+
+// QuantidadeAssinaturasYaml retorna quantas assinaturas de tipos de arquivo YAML estão carregadas.
+func QuantidadeAssinaturasYaml() int {
+	return len(assinaturasYaml)
+}
+
+// compilarRegexes compila uma lista de expressões regulares.
+func compilarRegexes(lista []string) ([]*regexp.Regexp, error) {
+	var compiladas []*regexp.Regexp
+	for _, expr := range lista {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("regex inválida %q: %w", expr, err)
+		}
+		compiladas = append(compiladas, re)
+	}
+	return compiladas, nil
+}
+
+// dividirCaminhos divide cada caminho ("production.adapter") nas suas chaves.
+func dividirCaminhos(caminhos []string) [][]string {
+	var divididos [][]string
+	for _, c := range caminhos {
+		divididos = append(divididos, strings.Split(strings.TrimSpace(c), "."))
+	}
+	return divididos
+}
+
+// AnalisarYaml interpreta o conteúdo como YAML (todos os documentos do arquivo), identifica o tipo do
+// arquivo pelas assinaturas e procura valores sensíveis em qualquer profundidade. Retorna false se o
+// conteúdo não for um YAML com mapas ou listas (texto simples também é um YAML válido).
+func AnalisarYaml(content string) (AnaliseYaml, bool) {
+	var documentos []interface{}
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if err != io.EOF && len(documentos) == 0 {
+				return AnaliseYaml{}, false
+			}
+			break
+		}
+		switch doc.(type) {
+		case map[interface{}]interface{}, []interface{}:
+			documentos = append(documentos, doc)
+		}
+	}
+	if len(documentos) == 0 {
+		return AnaliseYaml{}, false
+	}
+
+	var analise AnaliseYaml
+	lowerContent := strings.ToLower(content)
+	for _, a := range assinaturasYaml {
+		for _, doc := range documentos {
+			if a.casa(doc, lowerContent) {
+				analise.Tipos = append(analise.Tipos, a.nome)
+				break
+			}
+		}
+	}
+
+	for i, doc := range documentos {
+		var segredos []SegredoYaml
+		procurarSegredosYaml(doc, "", "", &segredos, make(map[string]bool))
+		// Em arquivos com vários documentos o caminho indica o documento (ex.: doc2:data.password)
+		for _, s := range segredos {
+			if len(documentos) > 1 {
+				s.Caminho = fmt.Sprintf("doc%d:%s", i+1, s.Caminho)
+			}
+			analise.Segredos = append(analise.Segredos, s)
+		}
+	}
+	return analise, true
+}
+
+// casa indica se o documento tem todos os caminhos de requer, ao menos um de algum e todos os trechos de conteudo.
+func (a assinaturaCompilada) casa(doc interface{}, lowerContent string) bool {
+	for _, c := range a.conteudo {
+		if !strings.Contains(lowerContent, c) {
+			return false
+		}
+	}
+	for _, caminho := range a.requer {
+		if !existeCaminho(doc, caminho) {
+			return false
+		}
+	}
+	if len(a.algum) == 0 {
+		return true
+	}
+	for _, caminho := range a.algum {
+		if existeCaminho(doc, caminho) {
+			return true
+		}
+	}
+	return false
+}
+
+// existeCaminho indica se o caminho de chaves existe a partir do nó ("*" casa com qualquer chave ou
+// item de lista, "**" com qualquer quantidade de níveis).
+func existeCaminho(no interface{}, chaves []string) bool {
+	if len(chaves) == 0 {
+		return true
+	}
+	chave, resto := chaves[0], chaves[1:]
+	if chave == "**" {
+		if existeCaminho(no, resto) {
+			return true
+		}
+		for _, f := range filhosYaml(no) {
+			if existeCaminho(f.valor, chaves) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, f := range filhosYaml(no) {
+		if (chave == "*" || (f.chave != "" && strings.EqualFold(f.chave, chave))) && existeCaminho(f.valor, resto) {
+			return true
+		}
+	}
+	return false
+}
+
+// filhosYaml retorna as chaves de um mapa (em ordem) ou os itens de uma lista.
+func filhosYaml(no interface{}) []filhoYaml {
+	var filhos []filhoYaml
+	switch v := no.(type) {
+	case map[interface{}]interface{}:
+		for k, valor := range v {
+			filhos = append(filhos, filhoYaml{chave: fmt.Sprint(k), valor: valor})
+		}
+		sort.Slice(filhos, func(i, j int) bool { return filhos[i].chave < filhos[j].chave })
+	case []interface{}:
+		for _, valor := range v {
+			filhos = append(filhos, filhoYaml{valor: valor})
+		}
+	}
+	return filhos
+}
+
+// procurarSegredosYaml percorre a árvore e acumula os valores sensíveis: valores de chaves sensíveis
+// ou valores que são segredos por si só (chave privada, URL com senha). Números e booleanos (ex.:
+// password_min_length: 8, token_ttl: 3600) não são segredos e são ignorados.
+func procurarSegredosYaml(no interface{}, caminho, chave string, segredos *[]SegredoYaml, vistos map[string]bool) {
+	switch v := no.(type) {
+	case map[interface{}]interface{}, []interface{}:
+		for i, f := range filhosYaml(v) {
+			if f.chave == "" {
+				// Item de lista: herda a chave da lista (ex.: passwords: [a, b])
+				procurarSegredosYaml(f.valor, fmt.Sprintf("%s[%d]", caminho, i), chave, segredos, vistos)
+				continue
+			}
+			filho := f.chave
+			if caminho != "" {
+				filho = caminho + "." + f.chave
+			}
+			procurarSegredosYaml(f.valor, filho, f.chave, segredos, vistos)
+		}
+	case string:
+		valor := strings.TrimSpace(v)
+		if valor == "" || vistos[caminho] || !valorSensivelYaml(chave, valor) {
+			return
+		}
+		vistos[caminho] = true
+		*segredos = append(*segredos, SegredoYaml{Caminho: caminho, Valor: valor})
+	}
+}
+
+// valorSensivelYaml indica se o valor (da chave informada) deve ser reportado. Valores encontrados só
+// pelo nome da chave precisam passar pelos mesmos limites de tamanho e entropia dos segredos genéricos
+// (ex.: api_key_header: X-Api-Key não é um segredo).
+func valorSensivelYaml(chave, valor string) bool {
+	for _, re := range valoresIgnoradosYaml {
+		if re.MatchString(valor) {
+			return false
+		}
+	}
+	if ehPlaceholder(valor) {
+		return false
+	}
+	for _, re := range valoresSensiveisYaml {
+		if re.MatchString(valor) {
+			return true
+		}
+	}
+	if len(valor) < tamanhoMinimoValor {
+		return false
+	}
+	minimo := entropiaMinima
+	if reChaveSenha.MatchString(chave) {
+		minimo = entropiaMinimaSenha
+	}
+	if entropiaShannon(valor) < minimo {
+		return false
+	}
+	for _, re := range chavesSensiveisYaml {
+		if re.MatchString(chave) {
+			return true
+		}
+	}
+	return false
+}
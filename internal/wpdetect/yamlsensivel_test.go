@@ -0,0 +1,95 @@
+package wpdetect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalisarYaml(t *testing.T) {
+	if err := CarregarAssinaturasYaml("../../paths/yaml_assinaturas.yml"); err != nil {
+		t.Fatal(err)
+	}
+	entropia, entropiaSenha, tamanho := entropiaMinima, entropiaMinimaSenha, tamanhoMinimoValor
+	exatos, regexes := placeholdersExatos, placeholdersRegex
+	t.Cleanup(func() {
+		entropiaMinima, entropiaMinimaSenha, tamanhoMinimoValor = entropia, entropiaSenha, tamanho
+		placeholdersExatos, placeholdersRegex = exatos, regexes
+	})
+	if err := ConfigurarSegredosGenericos(3.5, 2.5, 6, []string{"your_password_here"}); err != nil {
+		t.Fatal(err)
+	}
+	casos := []struct {
+		nome     string
+		conteudo string
+		yaml     bool
+		tipos    []string
+		caminhos []string
+	}{
+		{
+			nome: "rails com senha e template",
+			conteudo: `production:
+  adapter: mysql2
+  database: app
+  host: db
+  password: S3cr3tP4ss!
+development:
+  password: <%= ENV['DB_PASS'] %>
+`,
+			yaml:     true,
+			tipos:    []string{"Rails DB Config", "Database Config"},
+			caminhos: []string{"production.password"},
+		},
+		{
+			nome: "números, booleanos e nomes de cabeçalho não são segredos",
+			conteudo: `security:
+  password_min_length: 8
+  token_ttl: 3600
+  api_key_header: X-Api-Key
+  secret_enabled: true
+  password: your_password_here
+`,
+			yaml: true,
+		},
+		{
+			nome: "segredo aninhado em lista e URL com senha",
+			conteudo: `kind: pipeline
+steps:
+  - name: build
+    image: golang
+    environment:
+      API_TOKEN: 9f8e7d6c5b4a3f2e1d0c
+      DATABASE: postgres://app:hunter2x@db/app
+`,
+			yaml:     true,
+			tipos:    []string{"Drone Config"},
+			caminhos: []string{"steps[0].environment.API_TOKEN", "steps[0].environment.DATABASE"},
+		},
+		{
+			nome:     "vários documentos",
+			conteudo: "apiVersion: v1\nkind: Secret\nmetadata:\n  name: s\ndata:\n  password: cGFzc3dvcmQxMjM=\n---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: c\n",
+			yaml:     true,
+			tipos:    []string{"Kubernetes Manifest", "Kubernetes Secret"},
+			caminhos: []string{"doc1:data.password"},
+		},
+		{nome: "texto simples", conteudo: "not found"},
+		{nome: "YAML inválido", conteudo: "<b>x</b>: ["},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			analise, ok := AnalisarYaml(c.conteudo)
+			if ok != c.yaml {
+				t.Fatalf("AnalisarYaml = %v, esperado %v", ok, c.yaml)
+			}
+			if !reflect.DeepEqual(analise.Tipos, c.tipos) {
+				t.Errorf("tipos %v, esperado %v", analise.Tipos, c.tipos)
+			}
+			var caminhos []string
+			for _, s := range analise.Segredos {
+				caminhos = append(caminhos, s.Caminho)
+			}
+			if !reflect.DeepEqual(caminhos, c.caminhos) {
+				t.Errorf("segredos %v, esperado %v", caminhos, c.caminhos)
+			}
+		})
+	}
+}
@@ -2,7 +2,7 @@ package main
 
 import (
 	"fmt"
-	"log"
+	"os"
 
 	// Ajuste de acordo com o nome do seu módulo:
 	"Gowpscanner/internal/scanner"
@@ -13,7 +13,9 @@ func main() {
 	// 3) Executa o scanner (lendo dominios.txt)
 	err := scanner.Run("dominios.txt")
 	if err != nil {
-		log.Fatalf("Erro ao executar o scanner: %v", err)
+		// O pacote utils descarta a saída do log, então o erro é exibido diretamente
+		fmt.Printf("Erro ao executar o scanner: %v\n", err)
+		os.Exit(1)
 	}
 
 	fmt.Println("Scan finalizado.")